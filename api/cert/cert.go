@@ -106,50 +106,34 @@ func GetCSRList(ac *client.AlpaconClient, status string) ([]CSRAttributes, error
 }
 
 func GetAuthorityList(ac *client.AlpaconClient) ([]AuthorityAttributes, error) {
-	var authorityList []AuthorityAttributes
-	page := 1
-	const pageSize = 100
-
-	params := map[string]string{
-		"page":      strconv.Itoa(page),
-		"page_size": fmt.Sprintf("%d", pageSize),
+	authorities, err := ListAuthorities(ac)
+	if err != nil {
+		return nil, err
 	}
 
-	for {
-		responseBody, err := ac.SendGetRequest(utils.BuildURL(authorityURL, "", params))
-		if err != nil {
-			return nil, err
-		}
-
-		var response api.ListResponse[AuthorityResponse]
-		if err = json.Unmarshal(responseBody, &response); err != nil {
-			return nil, err
-		}
-
-		for _, authority := range response.Results {
-			authorityList = append(authorityList, AuthorityAttributes{
-				Id:               authority.Id,
-				Name:             authority.Name,
-				Organization:     authority.Organization,
-				Domain:           authority.Domain,
-				RootValidDays:    authority.RootValidDays,
-				DefaultValidDays: authority.DefaultValidDays,
-				MaxValidDays:     authority.MaxValidDays,
-				Server:           authority.AgentName,
-				Owner:            authority.OwnerName,
-				SignedAt:         utils.TimeUtils(authority.SignedAt),
-			})
-		}
-
-		if len(response.Results) < pageSize {
-			break
-		}
-		page++
+	var authorityList []AuthorityAttributes
+	for _, authority := range authorities {
+		authorityList = append(authorityList, AuthorityAttributes{
+			Id:               authority.Id,
+			Name:             authority.Name,
+			Organization:     authority.Organization,
+			Domain:           authority.Domain,
+			RootValidDays:    authority.RootValidDays,
+			DefaultValidDays: authority.DefaultValidDays,
+			MaxValidDays:     authority.MaxValidDays,
+			Server:           authority.AgentName,
+			Owner:            authority.OwnerName,
+			SignedAt:         utils.TimeUtils(authority.SignedAt),
+		})
 	}
-
 	return authorityList, nil
 }
 
+// ListAuthorities returns every certificate authority.
+func ListAuthorities(ac *client.AlpaconClient) ([]AuthorityResponse, error) {
+	return listAll[AuthorityResponse](ac, authorityURL)
+}
+
 func GetAuthorityDetail(ac *client.AlpaconClient, authorityId string) ([]byte, error) {
 	body, err := ac.SendGetRequest(utils.BuildURL(authorityURL, authorityId, nil))
 	if err != nil {
@@ -216,45 +200,58 @@ func DeleteCA(ac *client.AlpaconClient, authorityId string) error {
 }
 
 func GetCertificateList(ac *client.AlpaconClient) ([]CertificateAttributes, error) {
+	certs, err := ListCertificates(ac)
+	if err != nil {
+		return nil, err
+	}
+
 	var certList []CertificateAttributes
-	page := 1
+	for _, cert := range certs {
+		certList = append(certList, CertificateAttributes{
+			Id:        cert.Id,
+			Authority: cert.Authority,
+			Csr:       cert.Csr,
+			ValidDays: cert.ValidDays,
+			SignedAt:  utils.TimeUtils(cert.SignedAt),
+			ExpiresAt: utils.TimeUtils(cert.ExpiresAt),
+			SignedBy:  cert.SignedBy,
+			RenewedBy: cert.RenewedBy,
+		})
+	}
+	return certList, nil
+}
+
+// ListCertificates returns every issued certificate.
+func ListCertificates(ac *client.AlpaconClient) ([]Certificate, error) {
+	return listAll[Certificate](ac, certURL)
+}
+
+// listAll pages through the list at url.
+func listAll[T any](ac *client.AlpaconClient, url string) ([]T, error) {
+	var results []T
 	const pageSize = 100
 
-	params := map[string]string{
-		"page":      strconv.Itoa(page),
-		"page_size": fmt.Sprintf("%d", pageSize),
-	}
-	for {
-		responseBody, err := ac.SendGetRequest(utils.BuildURL(certURL, "", params))
+	for page := 1; ; page++ {
+		params := map[string]string{
+			"page":      strconv.Itoa(page),
+			"page_size": fmt.Sprintf("%d", pageSize),
+		}
+		responseBody, err := ac.SendGetRequest(utils.BuildURL(url, "", params))
 		if err != nil {
 			return nil, err
 		}
 
-		var response api.ListResponse[Certificate]
+		var response api.ListResponse[T]
 		if err = json.Unmarshal(responseBody, &response); err != nil {
 			return nil, err
 		}
-
-		for _, cert := range response.Results {
-			certList = append(certList, CertificateAttributes{
-				Id:        cert.Id,
-				Authority: cert.Authority,
-				Csr:       cert.Csr,
-				ValidDays: cert.ValidDays,
-				SignedAt:  utils.TimeUtils(cert.SignedAt),
-				ExpiresAt: utils.TimeUtils(cert.ExpiresAt),
-				SignedBy:  cert.SignedBy,
-				RenewedBy: cert.RenewedBy,
-			})
-		}
+		results = append(results, response.Results...)
 
 		if len(response.Results) < pageSize {
 			break
 		}
-		page++
 	}
-
-	return certList, nil
+	return results, nil
 }
 
 func DownloadCertificate(ac *client.AlpaconClient, certId string, filePath string) error {
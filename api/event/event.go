@@ -3,63 +3,231 @@ package event
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
 	"github.com/alpacanetworks/alpacon-cli/api"
 	"github.com/alpacanetworks/alpacon-cli/api/iam"
 	"github.com/alpacanetworks/alpacon-cli/api/server"
 	"github.com/alpacanetworks/alpacon-cli/client"
 	"github.com/alpacanetworks/alpacon-cli/utils"
-	"path"
+	"strconv"
+	"strings"
 	"time"
 )
 
 const (
-	getEventURL = "/api/events/commands/"
+	getEventURL  = "/api/events/commands/"
+	listPageSize = 100
+	// DefaultRunAs is the user the agent runs commands as when a command names no user.
+	DefaultRunAs = "root"
 )
 
-func GetEventList(ac *client.AlpaconClient, pageSize int, serverName string, userName string) ([]EventAttributes, error) {
-	var serverID, userID string
-	var err error
-	if serverName != "" {
-		serverID, err = server.GetServerIDByName(ac, serverName)
+// GetEventList returns the newest events matching filter, newest first.
+// A pageSize of 0 returns every matching event.
+func GetEventList(ac *client.AlpaconClient, pageSize int, filter EventFilter) ([]EventAttributes, error) {
+	events, err := ListEvents(ac, filter, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var eventList []EventAttributes
+	for _, event := range events {
+		eventList = append(eventList, EventAttributes{
+			ID:          event.ID,
+			Server:      event.ServerName,
+			Shell:       event.Shell,
+			Command:     event.Line,
+			Result:      utils.TruncateString(event.Result, 70),
+			Status:      utils.BoolPointerToString(event.Success),
+			Operator:    event.RequestedByName,
+			RequestedAt: utils.TimeUtils(event.AddedAt),
+		})
+	}
+	return eventList, nil
+}
+
+// ListEvents pages through the events matching filter, newest first, until limit events are found
+// or the events become older than filter.Since. A limit of 0 means no limit.
+// Filters are sent as query parameters and also applied locally, so every returned event matches.
+func ListEvents(ac *client.AlpaconClient, filter EventFilter, limit int) ([]EventDetails, error) {
+	params, err := filterParams(ac, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize := listPageSize
+	if limit > 0 && limit < pageSize {
+		pageSize = limit
+	}
+
+	var events []EventDetails
+	for page := 1; page != 0; {
+		results, next, err := fetchEventPage(ac, params, page, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		page = next
+
+		for _, event := range results {
+			if filter.matches(event) {
+				events = append(events, event)
+				if limit > 0 && len(events) == limit {
+					return events, nil
+				}
+			}
+		}
+
+		if len(results) == 0 {
+			break
+		}
+		if oldest := results[len(results)-1]; !filter.Since.IsZero() && oldest.AddedAt.Before(filter.Since) {
+			break
+		}
+	}
+	return events, nil
+}
+
+// filterParams maps filter to the query parameters of the events API.
+func filterParams(ac *client.AlpaconClient, filter EventFilter) (map[string]string, error) {
+	params := map[string]string{}
+	if filter.ServerName != "" {
+		serverID, err := server.GetServerIDByName(ac, filter.ServerName)
 		if err != nil {
 			return nil, err
 		}
+		params["server"] = serverID
 	}
-	if userName != "" {
-		userID, err = iam.GetUserIDByName(ac, userName)
+	if filter.UserName != "" {
+		userID, err := iam.GetUserIDByName(ac, filter.UserName)
 		if err != nil {
 			return nil, err
 		}
+		params["requested_by"] = userID
+	}
+	if !filter.Since.IsZero() {
+		params["added_at__gte"] = filter.Since.UTC().Format(time.RFC3339)
+	}
+	if !filter.Until.IsZero() {
+		params["added_at__lte"] = filter.Until.UTC().Format(time.RFC3339)
+	}
+	switch filter.Status {
+	case "success":
+		params["success"] = "true"
+	case "failed":
+		params["success"] = "false"
+	}
+	if filter.Shell != "" {
+		params["shell"] = filter.Shell
 	}
+	if filter.CommandContains != "" {
+		params["search"] = filter.CommandContains
+	}
+	// Events run as the default user name none, so those are only matched locally.
+	if filter.RunAs != "" && filter.RunAs != DefaultRunAs {
+		params["username"] = filter.RunAs
+	}
+	return params, nil
+}
 
-	relativePath := path.Join(serverID, userID)
-	params := map[string]string{
-		"page_size": fmt.Sprintf("%d", pageSize),
+// fetchEventPage returns one page of events, newest first, and the number of the next page, which is 0 after the last one.
+func fetchEventPage(ac *client.AlpaconClient, params map[string]string, page, pageSize int) ([]EventDetails, int, error) {
+	query := map[string]string{
+		"page":      strconv.Itoa(page),
+		"page_size": strconv.Itoa(pageSize),
 	}
-	responseBody, err := ac.SendGetRequest(utils.BuildURL(getEventURL, relativePath, params))
+	for key, value := range params {
+		query[key] = value
+	}
+
+	responseBody, err := ac.SendGetRequest(utils.BuildURL(getEventURL, "", query))
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	var response api.ListResponse[EventDetails]
 	if err = json.Unmarshal(responseBody, &response); err != nil {
-		return nil, err
+		return nil, 0, err
 	}
+	return response.Results, response.Next, nil
+}
 
-	var eventList []EventAttributes
-	for _, event := range response.Results {
-		eventList = append(eventList, EventAttributes{
-			Server:      event.ServerName,
-			Shell:       event.Shell,
-			Command:     event.Line,
-			Result:      utils.TruncateString(event.Result, 70),
-			Status:      utils.BoolPointerToString(event.Success),
-			Operator:    event.RequestedByName,
-			RequestedAt: utils.TimeUtils(event.AddedAt),
-		})
+// matches reports whether event satisfies the parts of filter that can be checked on the event itself.
+func (f EventFilter) matches(event EventDetails) bool {
+	if !f.Since.IsZero() && event.AddedAt.Before(f.Since) {
+		return false
 	}
-	return eventList, nil
+	if !f.Until.IsZero() && event.AddedAt.After(f.Until) {
+		return false
+	}
+	switch f.Status {
+	case "success":
+		if event.Success == nil || !*event.Success {
+			return false
+		}
+	case "failed":
+		if event.Success == nil || *event.Success {
+			return false
+		}
+	case "stuck":
+		if text, _ := event.Status["text"].(string); text != "Stuck" {
+			return false
+		}
+	}
+	if f.Shell != "" && event.Shell != f.Shell {
+		return false
+	}
+	if f.CommandContains != "" && !strings.Contains(event.Line, f.CommandContains) {
+		return false
+	}
+	if f.RunAs != "" && event.RunAs() != f.RunAs {
+		return false
+	}
+	return true
+}
+
+func GetEventDetail(ac *client.AlpaconClient, eventID string) (EventDetails, error) {
+	var event EventDetails
+	responseBody, err := ac.SendGetRequest(utils.BuildURL(getEventURL, eventID, nil))
+	if err != nil {
+		return event, err
+	}
+
+	err = json.Unmarshal(responseBody, &event)
+	return event, err
+}
+
+// RunAs returns the user the command of e ran as on the server, which is DefaultRunAs when e names none.
+func (e EventDetails) RunAs() string {
+	if e.Username == "" {
+		return DefaultRunAs
+	}
+	return e.Username
+}
+
+// RerunEvent submits the command of event again, on the same server and as the same user and group.
+// It returns the ID of the new event.
+func RerunEvent(ac *client.AlpaconClient, event EventDetails) (string, error) {
+	commandRequest := &CommandRequest{
+		Shell:     event.Shell,
+		Line:      event.Line,
+		Env:       event.Env,
+		Data:      event.Data,
+		Username:  event.Username,
+		Groupname: event.Groupname,
+		Server:    event.Server,
+		RunAfter:  []string{},
+	}
+	respBody, err := ac.SendPostRequest(getEventURL, commandRequest)
+	if err != nil {
+		return "", err
+	}
+
+	var cmdResponse []CommandResponse
+	if err = json.Unmarshal(respBody, &cmdResponse); err != nil {
+		return "", err
+	}
+	if len(cmdResponse) == 0 {
+		return "", errors.New("the server did not return the new event")
+	}
+	return cmdResponse[0].Id, nil
 }
 
 func RunCommand(ac *client.AlpaconClient, serverName, command string, username, groupname string, env map[string]string) (string, error) {
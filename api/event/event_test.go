@@ -0,0 +1,81 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/alpacanetworks/alpacon-cli/api"
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestEventFilterMatches(t *testing.T) {
+	success, failure := true, false
+	base := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	event := EventDetails{
+		Shell:   "system",
+		Line:    "systemctl restart nginx",
+		Success: &failure,
+		AddedAt: base,
+	}
+
+	assert.True(t, EventFilter{}.matches(event))
+	assert.True(t, EventFilter{Since: base.Add(-time.Hour), Until: base.Add(time.Hour)}.matches(event))
+	assert.False(t, EventFilter{Since: base.Add(time.Minute)}.matches(event))
+	assert.False(t, EventFilter{Until: base.Add(-time.Minute)}.matches(event))
+
+	assert.True(t, EventFilter{Status: "failed"}.matches(event))
+	assert.False(t, EventFilter{Status: "success"}.matches(event))
+	assert.False(t, EventFilter{Status: "stuck"}.matches(event))
+	event.Success = &success
+	assert.True(t, EventFilter{Status: "success"}.matches(event))
+	event.Success = nil
+	event.Status = map[string]interface{}{"text": "Stuck"}
+	assert.True(t, EventFilter{Status: "stuck"}.matches(event))
+
+	assert.True(t, EventFilter{Shell: "system", CommandContains: "restart"}.matches(event))
+	assert.False(t, EventFilter{Shell: "osquery"}.matches(event))
+	assert.False(t, EventFilter{CommandContains: "reload"}.matches(event))
+
+	event.Username = "root"
+	assert.True(t, EventFilter{RunAs: "root"}.matches(event))
+	assert.False(t, EventFilter{RunAs: "www-data"}.matches(event))
+
+	// Commands naming no user run as the default user.
+	event.Username = ""
+	assert.True(t, EventFilter{RunAs: DefaultRunAs}.matches(event))
+	assert.False(t, EventFilter{RunAs: "www-data"}.matches(event))
+}
+
+func TestListEventsStopsAtLastPage(t *testing.T) {
+	var requested []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		requested = append(requested, r.URL.Query().Get("page"))
+		if page > 2 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		response := api.ListResponse[EventDetails]{Count: 2 * listPageSize, Current: page}
+		if page == 1 {
+			response.Next = 2
+		}
+		for i := 0; i < listPageSize; i++ {
+			response.Results = append(response.Results, EventDetails{ID: fmt.Sprintf("%d-%d", page, i)})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer srv.Close()
+
+	ac := &client.AlpaconClient{HTTPClient: srv.Client(), BaseURL: srv.URL}
+	events, err := ListEvents(ac, EventFilter{}, 0)
+	assert.NoError(t, err)
+	assert.Len(t, events, 2*listPageSize)
+	assert.Equal(t, []string{"1", "2"}, requested)
+}
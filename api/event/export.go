@@ -0,0 +1,135 @@
+package event
+
+import (
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"strconv"
+	"time"
+)
+
+// ExportCheckpoint records how far events have been exported, so the next export starts after them.
+// Event IDs are not ordered, so the checkpoint keeps the newest request time and the events requested at that time.
+type ExportCheckpoint struct {
+	AddedAt time.Time `json:"added_at"`
+	IDs     []string  `json:"ids"`
+}
+
+// Advance moves the checkpoint past events.
+func (c *ExportCheckpoint) Advance(events []EventDetails) {
+	for _, event := range events {
+		switch {
+		case event.AddedAt.After(c.AddedAt):
+			c.AddedAt = event.AddedAt
+			c.IDs = []string{event.ID}
+		case event.AddedAt.Equal(c.AddedAt) && !c.exported(event):
+			c.IDs = append(c.IDs, event.ID)
+		}
+	}
+}
+
+// exported reports whether event is covered by the checkpoint.
+func (c ExportCheckpoint) exported(event EventDetails) bool {
+	if event.AddedAt.Before(c.AddedAt) {
+		return true
+	}
+	if event.AddedAt.After(c.AddedAt) {
+		return false
+	}
+	for _, id := range c.IDs {
+		if id == event.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// ListEventsAfter returns every event matching filter that was requested after the checkpoint, oldest first.
+// It stops before the oldest event that has not finished yet, so a checkpoint advanced over the result never
+// passes a running event, and the next export picks it up with its outcome.
+func ListEventsAfter(ac *client.AlpaconClient, filter EventFilter, checkpoint ExportCheckpoint) ([]EventDetails, error) {
+	if checkpoint.AddedAt.After(filter.Since) {
+		filter.Since = checkpoint.AddedAt
+	}
+
+	events, err := ListEvents(ac, filter, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var fresh []EventDetails
+	for _, event := range events {
+		if !checkpoint.exported(event) {
+			fresh = append(fresh, event)
+		}
+	}
+	sortByAddedAt(fresh)
+	return finishedPrefix(fresh), nil
+}
+
+// finishedPrefix returns the events before the first one that has not finished yet.
+func finishedPrefix(events []EventDetails) []EventDetails {
+	for i, event := range events {
+		if !event.finished() {
+			return events[:i]
+		}
+	}
+	return events
+}
+
+// finished reports whether event has an outcome. Stuck and errored commands never get one, so they count as finished.
+func (event EventDetails) finished() bool {
+	if event.Success != nil {
+		return true
+	}
+	text, _ := event.Status["text"].(string)
+	return text == "Stuck" || text == "Error"
+}
+
+func (event EventDetails) CSVHeader() []string {
+	return []string{"id", "added_at", "server", "requested_by", "username", "groupname", "shell", "line",
+		"success", "elapsed_time", "result"}
+}
+
+func (event EventDetails) CSVRecord() []string {
+	return []string{
+		event.ID,
+		event.AddedAt.UTC().Format(time.RFC3339Nano),
+		event.ServerName,
+		event.RequestedByName,
+		event.Username,
+		event.Groupname,
+		event.Shell,
+		event.Line,
+		utils.BoolPointerToString(event.Success),
+		strconv.FormatFloat(event.ElapsedTime, 'f', -1, 64),
+		event.Result,
+	}
+}
+
+// Syslog returns event as an authpriv syslog message whose text is the command line.
+// Failed commands are reported as errors.
+func (event EventDetails) Syslog() utils.SyslogMessage {
+	severity := utils.SyslogSeverityInfo
+	if event.Success != nil && !*event.Success {
+		severity = utils.SyslogSeverityError
+	}
+
+	return utils.SyslogMessage{
+		Facility:  utils.SyslogFacilityAuthPriv,
+		Severity:  severity,
+		Timestamp: event.AddedAt,
+		Hostname:  event.ServerName,
+		AppName:   "alpacon",
+		MsgID:     "command",
+		Params: map[string]string{
+			"id":           event.ID,
+			"requested_by": event.RequestedByName,
+			"username":     event.Username,
+			"groupname":    event.Groupname,
+			"shell":        event.Shell,
+			"success":      utils.BoolPointerToString(event.Success),
+			"elapsed_time": strconv.FormatFloat(event.ElapsedTime, 'f', -1, 64),
+		},
+		Message: event.Line,
+	}
+}
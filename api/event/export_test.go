@@ -0,0 +1,51 @@
+package event
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestExportCheckpoint(t *testing.T) {
+	base := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	a := EventDetails{ID: "a", AddedAt: base}
+	b := EventDetails{ID: "b", AddedAt: base.Add(time.Second)}
+	c := EventDetails{ID: "c", AddedAt: base.Add(time.Second)}
+
+	var checkpoint ExportCheckpoint
+	checkpoint.Advance([]EventDetails{a, b})
+	assert.Equal(t, base.Add(time.Second), checkpoint.AddedAt)
+	assert.Equal(t, []string{"b"}, checkpoint.IDs)
+
+	// An event requested at the same time as the last exported one is still new.
+	assert.True(t, checkpoint.exported(a))
+	assert.True(t, checkpoint.exported(b))
+	assert.False(t, checkpoint.exported(c))
+
+	checkpoint.Advance([]EventDetails{c})
+	assert.Equal(t, []string{"b", "c"}, checkpoint.IDs)
+}
+
+func TestFinishedPrefixHoldsBackRunningEvents(t *testing.T) {
+	base := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	success := true
+	running := EventDetails{ID: "running", AddedAt: base}
+	finished := EventDetails{ID: "finished", AddedAt: base.Add(time.Second), Success: &success}
+
+	var checkpoint ExportCheckpoint
+	batch := finishedPrefix([]EventDetails{running, finished})
+	assert.Empty(t, batch)
+	checkpoint.Advance(batch)
+	assert.False(t, checkpoint.exported(running), "a running event must be exported once it finishes")
+	assert.False(t, checkpoint.exported(finished))
+
+	running.Success = &success
+	batch = finishedPrefix([]EventDetails{running, finished})
+	assert.Equal(t, []EventDetails{running, finished}, batch)
+	checkpoint.Advance(batch)
+	assert.True(t, checkpoint.exported(running))
+	assert.True(t, checkpoint.exported(finished))
+
+	stuck := EventDetails{ID: "stuck", AddedAt: base, Status: map[string]interface{}{"text": "Stuck"}}
+	assert.Len(t, finishedPrefix([]EventDetails{stuck, finished}), 2)
+}
@@ -0,0 +1,120 @@
+package event
+
+import (
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"sort"
+	"time"
+)
+
+const (
+	followPageSize = 100
+	// followMaxPages bounds how far back a single poll pages when many events arrived at once.
+	followMaxPages = 10
+	// followSkew is how far before the newest seen event a poll still looks, for events stored out of order.
+	followSkew = time.Minute
+)
+
+// FollowEvents reports command events as they are requested, oldest first, by polling every interval.
+// The last tail events are reported first. Events are de-duplicated by ID, so each is reported exactly once.
+// Failed polls are retried after refreshing an expired access token, so FollowEvents only returns when the initial request fails.
+func FollowEvents(ac *client.AlpaconClient, filter EventFilter, tail int, interval time.Duration, fn func(EventDetails)) error {
+	params, err := filterParams(ac, filter)
+	if err != nil {
+		return err
+	}
+
+	initial, _, err := fetchEventPage(ac, params, 1, followPageSize)
+	if err != nil {
+		return err
+	}
+
+	follower := &eventFollower{seen: make(map[string]time.Time)}
+	var matching []EventDetails
+	sortByAddedAt(initial)
+	for _, event := range initial {
+		follower.mark(event)
+		if filter.matches(event) {
+			matching = append(matching, event)
+		}
+	}
+	if len(matching) > tail {
+		matching = matching[len(matching)-tail:]
+	}
+	for _, event := range matching {
+		fn(event)
+	}
+
+	for {
+		time.Sleep(interval)
+
+		events, err := fetchEventsSince(ac, params, follower.cursor.Add(-followSkew))
+		if err != nil {
+			utils.CliWarning("Failed to poll events: %s. Retrying.", err)
+			if err = ac.RefreshAccessTokenIfExpired(); err != nil {
+				utils.CliWarning("%s.", err)
+			}
+			continue
+		}
+
+		sortByAddedAt(events)
+		for _, event := range events {
+			if follower.mark(event) && filter.matches(event) {
+				fn(event)
+			}
+		}
+		follower.prune()
+	}
+}
+
+// eventFollower tracks the events reported so far.
+type eventFollower struct {
+	seen   map[string]time.Time
+	cursor time.Time
+}
+
+// mark records event and reports whether it had not been seen before.
+func (f *eventFollower) mark(event EventDetails) bool {
+	if _, ok := f.seen[event.ID]; ok {
+		return false
+	}
+	f.seen[event.ID] = event.AddedAt
+	if event.AddedAt.After(f.cursor) {
+		f.cursor = event.AddedAt
+	}
+	return true
+}
+
+// prune forgets events too old to be returned by the next poll.
+func (f *eventFollower) prune() {
+	limit := f.cursor.Add(-2 * followSkew)
+	for id, addedAt := range f.seen {
+		if addedAt.Before(limit) {
+			delete(f.seen, id)
+		}
+	}
+}
+
+// fetchEventsSince pages through the newest events until it reaches one added before since.
+func fetchEventsSince(ac *client.AlpaconClient, params map[string]string, since time.Time) ([]EventDetails, error) {
+	var events []EventDetails
+	for page, fetched := 1, 0; page != 0 && fetched < followMaxPages; fetched++ {
+		results, next, err := fetchEventPage(ac, params, page, followPageSize)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, results...)
+		page = next
+
+		if len(results) == 0 || results[len(results)-1].AddedAt.Before(since) {
+			break
+		}
+	}
+	return events, nil
+}
+
+func sortByAddedAt(events []EventDetails) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].AddedAt.Before(events[j].AddedAt)
+	})
+}
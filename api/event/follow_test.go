@@ -0,0 +1,33 @@
+package event
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestEventFollowerDeduplicates(t *testing.T) {
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	follower := &eventFollower{seen: make(map[string]time.Time)}
+
+	assert.True(t, follower.mark(EventDetails{ID: "a", AddedAt: base}))
+	assert.True(t, follower.mark(EventDetails{ID: "b", AddedAt: base.Add(time.Second)}))
+	assert.False(t, follower.mark(EventDetails{ID: "a", AddedAt: base}))
+	assert.Equal(t, base.Add(time.Second), follower.cursor)
+
+	// An event stored late with an older timestamp is still reported once.
+	assert.True(t, follower.mark(EventDetails{ID: "c", AddedAt: base.Add(-time.Second)}))
+	assert.Equal(t, base.Add(time.Second), follower.cursor)
+
+	follower.mark(EventDetails{ID: "d", AddedAt: base.Add(10 * time.Minute)})
+	follower.prune()
+	assert.Equal(t, []string{"d"}, keys(follower.seen))
+}
+
+func keys(m map[string]time.Time) []string {
+	var result []string
+	for key := range m {
+		result = append(result, key)
+	}
+	return result
+}
@@ -0,0 +1,137 @@
+package event
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ReportRow aggregates the events sharing the same user, server or command.
+type ReportRow struct {
+	Key   string
+	Count int
+	// Finished counts the events that have completed, successfully or not.
+	Finished    int
+	Failed      int
+	MeanElapsed float64
+	// UnlistedUsers are the requesters of these events that are not on the allowlist.
+	UnlistedUsers []string
+}
+
+// FailureRate returns the share of failed events among the finished events of the row as a percentage.
+// Events that are still running or stuck have no outcome yet, so they are left out.
+func (r ReportRow) FailureRate() float64 {
+	if r.Finished == 0 {
+		return 0
+	}
+	return float64(r.Failed) * 100 / float64(r.Finished)
+}
+
+type ReportAttributes struct {
+	Key           string `json:"key"`
+	Count         int    `json:"count"`
+	Failed        int    `json:"failed"`
+	FailureRate   string `json:"failure_rate"`
+	MeanElapsed   string `json:"mean_elapsed"`
+	UnlistedUsers string `json:"unlisted_users"`
+}
+
+// ReportGroups lists the values accepted by BuildReport for groupBy.
+var ReportGroups = []string{"user", "server", "command"}
+
+// ValidReportGroup reports whether groupBy is one of ReportGroups.
+func ValidReportGroup(groupBy string) bool {
+	for _, group := range ReportGroups {
+		if groupBy == group {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildReport groups events by groupBy and aggregates their counts, failures and mean elapsed time.
+// When allowlist is not empty, requesters missing from it are listed per row.
+// Rows are ordered by descending count.
+func BuildReport(events []EventDetails, groupBy string, allowlist []string) ([]ReportRow, error) {
+	if !ValidReportGroup(groupBy) {
+		return nil, fmt.Errorf("invalid group %q: choose one of %s", groupBy, strings.Join(ReportGroups, ", "))
+	}
+
+	allowed := make(map[string]bool)
+	for _, user := range allowlist {
+		allowed[user] = true
+	}
+
+	type aggregate struct {
+		row      ReportRow
+		elapsed  float64
+		unlisted map[string]bool
+	}
+	groups := make(map[string]*aggregate)
+
+	for _, event := range events {
+		var key string
+		switch groupBy {
+		case "user":
+			key = event.RequestedByName
+		case "server":
+			key = event.ServerName
+		case "command":
+			key = event.Line
+		}
+
+		group, ok := groups[key]
+		if !ok {
+			group = &aggregate{row: ReportRow{Key: key}, unlisted: make(map[string]bool)}
+			groups[key] = group
+		}
+
+		group.row.Count++
+		if event.Success != nil {
+			group.row.Finished++
+			group.elapsed += event.ElapsedTime
+			if !*event.Success {
+				group.row.Failed++
+			}
+		}
+		if len(allowed) > 0 && !allowed[event.RequestedByName] {
+			group.unlisted[event.RequestedByName] = true
+		}
+	}
+
+	var rows []ReportRow
+	for _, group := range groups {
+		if group.row.Finished > 0 {
+			group.row.MeanElapsed = group.elapsed / float64(group.row.Finished)
+		}
+		for user := range group.unlisted {
+			group.row.UnlistedUsers = append(group.row.UnlistedUsers, user)
+		}
+		sort.Strings(group.row.UnlistedUsers)
+		rows = append(rows, group.row)
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Count != rows[j].Count {
+			return rows[i].Count > rows[j].Count
+		}
+		return rows[i].Key < rows[j].Key
+	})
+	return rows, nil
+}
+
+// ReportAttributesList formats rows for display.
+func ReportAttributesList(rows []ReportRow) []ReportAttributes {
+	var attributes []ReportAttributes
+	for _, row := range rows {
+		attributes = append(attributes, ReportAttributes{
+			Key:           row.Key,
+			Count:         row.Count,
+			Failed:        row.Failed,
+			FailureRate:   fmt.Sprintf("%.1f%%", row.FailureRate()),
+			MeanElapsed:   fmt.Sprintf("%.2fs", row.MeanElapsed),
+			UnlistedUsers: strings.Join(row.UnlistedUsers, ", "),
+		})
+	}
+	return attributes
+}
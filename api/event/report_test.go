@@ -0,0 +1,35 @@
+package event
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBuildReport(t *testing.T) {
+	success, failure := true, false
+	events := []EventDetails{
+		{RequestedByName: "alice", ServerName: "web-1", Line: "uptime", Success: &success, ElapsedTime: 1},
+		{RequestedByName: "bob", ServerName: "web-1", Line: "uptime", Success: &failure, ElapsedTime: 3},
+		{RequestedByName: "mallory", ServerName: "db-1", Line: "cat /etc/shadow", Success: &success, ElapsedTime: 2},
+		{RequestedByName: "alice", ServerName: "web-1", Line: "uptime"},
+	}
+
+	rows, err := BuildReport(events, "command", []string{"alice", "bob"})
+	assert.NoError(t, err)
+	assert.Equal(t, []ReportRow{
+		{Key: "uptime", Count: 3, Finished: 2, Failed: 1, MeanElapsed: 2},
+		{Key: "cat /etc/shadow", Count: 1, Finished: 1, MeanElapsed: 2, UnlistedUsers: []string{"mallory"}},
+	}, rows)
+	// The running event has no outcome yet, so it does not count towards the failure rate.
+	assert.InDelta(t, 50, rows[0].FailureRate(), 0.1)
+
+	rows, err = BuildReport(events, "server", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "web-1", rows[0].Key)
+	assert.Nil(t, rows[0].UnlistedUsers)
+
+	_, err = BuildReport(events, "shell", nil)
+	assert.Error(t, err)
+	assert.False(t, ValidReportGroup("shell"))
+	assert.True(t, ValidReportGroup("user"))
+}
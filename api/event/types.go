@@ -3,6 +3,7 @@ package event
 import "time"
 
 type EventAttributes struct {
+	ID          string `json:"id"`
 	Server      string `json:"server"`
 	Shell       string `json:"shell"`
 	Command     string `json:"command"`
@@ -12,6 +13,18 @@ type EventAttributes struct {
 	RequestedAt string `json:"requested_at"`
 }
 
+// EventFilter selects command events. Zero values do not filter.
+type EventFilter struct {
+	ServerName      string
+	UserName        string
+	Since           time.Time
+	Until           time.Time
+	Status          string // success, failed or stuck
+	Shell           string
+	CommandContains string
+	RunAs           string
+}
+
 type EventDetails struct {
 	ID              string                 `json:"id"`
 	Shell           string                 `json:"shell"`
@@ -26,6 +39,10 @@ type EventDetails struct {
 	ServerName      string                 `json:"server_name"`
 	RequestedBy     string                 `json:"requested_by"`
 	RequestedByName string                 `json:"requested_by_name"`
+	Username        string                 `json:"username"`
+	Groupname       string                 `json:"groupname"`
+	Env             map[string]string      `json:"env"`
+	Data            string                 `json:"data"`
 }
 
 type CommandRequest struct {
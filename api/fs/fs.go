@@ -0,0 +1,241 @@
+package fs
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/alpacanetworks/alpacon-cli/api/event"
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// entryFormat prints one tab-separated line per file: type, mode, size, owner, group, mtime, name, path, link target.
+	entryFormat = `%y\t%M\t%s\t%u\t%g\t%T@\t%f\t%p\t%l\n`
+	// okMarker is echoed after a successful command so that failures can be told apart from regular output.
+	okMarker = "__alpacon_ok__"
+)
+
+var fileTypes = map[string]string{
+	"f": "file",
+	"d": "directory",
+	"l": "symlink",
+	"b": "block",
+	"c": "char",
+	"p": "fifo",
+	"s": "socket",
+}
+
+func ListFiles(ac *client.AlpaconClient, serverName, remotePath, username, groupname string, all bool) ([]FileAttributes, error) {
+	quoted := utils.ShellQuote(remotePath)
+	command := fmt.Sprintf("if [ -d %s ]; then find %s -mindepth 1 -maxdepth 1 -printf '%s'; else find %s -maxdepth 0 -printf '%s'; fi",
+		quoted, quoted, entryFormat, quoted, entryFormat)
+
+	entries, err := findEntries(ac, serverName, command, username, groupname)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+
+	var fileList []FileAttributes
+	for _, entry := range entries {
+		if !all && strings.HasPrefix(entry.Name, ".") {
+			continue
+		}
+
+		name := entry.Name
+		if entry.Target != "" {
+			name = fmt.Sprintf("%s -> %s", entry.Name, entry.Target)
+		}
+		fileList = append(fileList, FileAttributes{
+			Name:     name,
+			Type:     entry.Type,
+			Mode:     entry.Mode,
+			Size:     utils.FormatBytes(entry.Size),
+			Owner:    entry.Owner,
+			Group:    entry.Group,
+			Modified: utils.TimeUtils(entry.ModTime),
+		})
+	}
+	return fileList, nil
+}
+
+func GetFileDetail(ac *client.AlpaconClient, serverName, remotePath, username, groupname string) (FileDetails, error) {
+	command := fmt.Sprintf("find %s -maxdepth 0 -printf '%s'", utils.ShellQuote(remotePath), entryFormat)
+
+	entries, err := findEntries(ac, serverName, command, username, groupname)
+	if err != nil {
+		return FileDetails{}, err
+	}
+	if len(entries) != 1 {
+		return FileDetails{}, fmt.Errorf("no such file or directory: %s", remotePath)
+	}
+
+	entry := entries[0]
+	return FileDetails{
+		Path:     entry.Path,
+		Type:     entry.Type,
+		Mode:     entry.Mode,
+		Size:     entry.Size,
+		Owner:    entry.Owner,
+		Group:    entry.Group,
+		Modified: entry.ModTime.Format(time.RFC3339),
+		Target:   entry.Target,
+	}, nil
+}
+
+func RemoveFile(ac *client.AlpaconClient, serverName string, remotePaths []string, username, groupname string, recursive bool) error {
+	command := "rm -f"
+	if recursive {
+		command = "rm -rf"
+	}
+	return runChecked(ac, serverName, command+" -- "+quoteAll(remotePaths), username, groupname)
+}
+
+func MakeDirectory(ac *client.AlpaconClient, serverName string, remotePaths []string, username, groupname string, parents bool) error {
+	command := "mkdir"
+	if parents {
+		command = "mkdir -p"
+	}
+	return runChecked(ac, serverName, command+" -- "+quoteAll(remotePaths), username, groupname)
+}
+
+func MoveFile(ac *client.AlpaconClient, serverName, src, dest, username, groupname string) error {
+	return runChecked(ac, serverName, "mv -- "+quoteAll([]string{src, dest}), username, groupname)
+}
+
+func ChangeMode(ac *client.AlpaconClient, serverName, mode string, remotePaths []string, username, groupname string, recursive bool) error {
+	if mode == "" || strings.HasPrefix(mode, "-") {
+		return fmt.Errorf("invalid mode: %q", mode)
+	}
+
+	command := "chmod"
+	if recursive {
+		command = "chmod -R"
+	}
+	return runChecked(ac, serverName, fmt.Sprintf("%s %s -- %s", command, utils.ShellQuote(mode), quoteAll(remotePaths)), username, groupname)
+}
+
+// OctalMode converts a symbolic mode as printed by ls, such as "-rwsr-xr-x", to the octal form accepted by chmod.
+func OctalMode(symbolic string) (string, error) {
+	if len(symbolic) != 10 {
+		return "", fmt.Errorf("invalid mode: %q", symbolic)
+	}
+
+	var mode int
+	for i := 0; i < 9; i++ {
+		c := symbolic[i+1]
+		bit := 1 << (8 - i)
+		switch {
+		case c == "rwxrwxrwx"[i]:
+			mode |= bit
+		case c == '-':
+		case i == 2 && (c == 's' || c == 'S'):
+			mode |= 0o4000
+		case i == 5 && (c == 's' || c == 'S'):
+			mode |= 0o2000
+		case i == 8 && (c == 't' || c == 'T'):
+			mode |= 0o1000
+		default:
+			return "", fmt.Errorf("invalid mode: %q", symbolic)
+		}
+		// Lowercase s and t also grant execute permission.
+		if c == 's' || c == 't' {
+			mode |= bit
+		}
+	}
+	return fmt.Sprintf("%04o", mode), nil
+}
+
+// runChecked runs command and returns its output as an error unless it succeeded.
+func runChecked(ac *client.AlpaconClient, serverName, command, username, groupname string) error {
+	result, err := event.RunCommand(ac, serverName, fmt.Sprintf("%s && echo %s", command, okMarker), username, groupname, nil)
+	if err != nil {
+		return err
+	}
+
+	output := strings.TrimSpace(result)
+	if !strings.HasSuffix(output, okMarker) {
+		if output == "" {
+			return errors.New("command failed without output")
+		}
+		return errors.New(output)
+	}
+	return nil
+}
+
+func findEntries(ac *client.AlpaconClient, serverName, command, username, groupname string) ([]FileEntry, error) {
+	result, err := event.RunCommand(ac, serverName, command, username, groupname, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := parseEntries(result)
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// parseEntries parses the output of find -printf with entryFormat.
+// Any line that does not match the format is treated as an error message from the server.
+func parseEntries(output string) ([]FileEntry, error) {
+	var entries []FileEntry
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) != 9 {
+			return nil, errors.New(strings.TrimSpace(output))
+		}
+		size, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return nil, errors.New(strings.TrimSpace(output))
+		}
+		mtime, err := strconv.ParseFloat(fields[5], 64)
+		if err != nil {
+			return nil, errors.New(strings.TrimSpace(output))
+		}
+
+		fileType, ok := fileTypes[fields[0]]
+		if !ok {
+			fileType = fields[0]
+		}
+
+		entries = append(entries, FileEntry{
+			Type:    fileType,
+			Mode:    fields[1],
+			Size:    size,
+			Owner:   fields[3],
+			Group:   fields[4],
+			ModTime: time.Unix(int64(mtime), 0),
+			Name:    fields[6],
+			Path:    fields[7],
+			Target:  fields[8],
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func quoteAll(paths []string) string {
+	quoted := make([]string, len(paths))
+	for i, p := range paths {
+		quoted[i] = utils.ShellQuote(p)
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,43 @@
+package fs
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestParseEntries(t *testing.T) {
+	output := "d\tdrwxr-xr-x\t4096\troot\troot\t1700000000.1234567890\tconf.d\t/etc/nginx/conf.d\t\n" +
+		"l\tlrwxrwxrwx\t22\troot\troot\t1700000001.0000000000\tsites\t/etc/nginx/sites\t/etc/nginx/sites-enabled\n"
+
+	entries, err := parseEntries(output)
+	assert.NoError(t, err)
+	assert.Equal(t, []FileEntry{
+		{Name: "conf.d", Path: "/etc/nginx/conf.d", Type: "directory", Mode: "drwxr-xr-x", Size: 4096,
+			Owner: "root", Group: "root", ModTime: time.Unix(1700000000, 0)},
+		{Name: "sites", Path: "/etc/nginx/sites", Type: "symlink", Mode: "lrwxrwxrwx", Size: 22,
+			Owner: "root", Group: "root", ModTime: time.Unix(1700000001, 0), Target: "/etc/nginx/sites-enabled"},
+	}, entries)
+}
+
+func TestParseEntriesReturnsServerError(t *testing.T) {
+	_, err := parseEntries("find: '/root/secret': Permission denied\n")
+	assert.EqualError(t, err, "find: '/root/secret': Permission denied")
+}
+
+func TestOctalMode(t *testing.T) {
+	for symbolic, octal := range map[string]string{
+		"-rw-r--r--": "0644",
+		"drwxr-xr-x": "0755",
+		"-rwsr-xr-x": "4755",
+		"-rwxr-Sr--": "2744",
+		"drwxrwxrwt": "1777",
+	} {
+		mode, err := OctalMode(symbolic)
+		assert.NoError(t, err)
+		assert.Equal(t, octal, mode, symbolic)
+	}
+
+	_, err := OctalMode("rw-r--r--")
+	assert.Error(t, err)
+}
@@ -0,0 +1,37 @@
+package fs
+
+import "time"
+
+// FileEntry is a file on a server as reported by find -printf.
+type FileEntry struct {
+	Name    string
+	Path    string
+	Type    string
+	Mode    string
+	Size    int64
+	Owner   string
+	Group   string
+	ModTime time.Time
+	Target  string
+}
+
+type FileAttributes struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Mode     string `json:"mode"`
+	Size     string `json:"size"`
+	Owner    string `json:"owner"`
+	Group    string `json:"group"`
+	Modified string `json:"modified"`
+}
+
+type FileDetails struct {
+	Path     string `json:"path"`
+	Type     string `json:"type"`
+	Mode     string `json:"mode"`
+	Size     int64  `json:"size"`
+	Owner    string `json:"owner"`
+	Group    string `json:"group"`
+	Modified string `json:"modified"`
+	Target   string `json:"target,omitempty"`
+}
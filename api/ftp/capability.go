@@ -0,0 +1,47 @@
+package ftp
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"sync"
+)
+
+// uploadMetadata is the part of the OPTIONS response of the uploads endpoint that lists the fields POST accepts.
+type uploadMetadata struct {
+	Actions struct {
+		Post map[string]json.RawMessage `json:"POST"`
+	} `json:"actions"`
+}
+
+var (
+	multipartSupportMu sync.Mutex
+	multipartSupport   = map[string]bool{}
+)
+
+// supportsMultipart reports whether the Alpacon server of ac advertises the multipart field for uploads.
+// Servers that do not, or that cannot be asked, receive every file with a single PUT, which is the default.
+// The answer is cached per server for the lifetime of the process.
+func supportsMultipart(ac *client.AlpaconClient) bool {
+	multipartSupportMu.Lock()
+	defer multipartSupportMu.Unlock()
+
+	if supported, ok := multipartSupport[ac.BaseURL]; ok {
+		return supported
+	}
+
+	supported := false
+	respBody, err := ac.SendOptionsRequest(uploadAPIURL)
+	if err == nil {
+		var metadata uploadMetadata
+		if json.Unmarshal(respBody, &metadata) == nil {
+			_, supported = metadata.Actions.Post["multipart"]
+		}
+	}
+	multipartSupport[ac.BaseURL] = supported
+	return supported
+}
+
+func errNoMultipart(name string) error {
+	return fmt.Errorf("the server does not accept multipart uploads, so %s cannot be streamed without a known size", name)
+}
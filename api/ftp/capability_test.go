@@ -0,0 +1,37 @@
+package ftp
+
+import (
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSupportsMultipart(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		status   int
+		body     string
+		expected bool
+	}{
+		{name: "advertised", status: http.StatusOK, body: `{"actions": {"POST": {"name": {}, "multipart": {"type": "boolean"}}}}`, expected: true},
+		{name: "not advertised", status: http.StatusOK, body: `{"actions": {"POST": {"name": {}}}}`},
+		{name: "not allowed", status: http.StatusMethodNotAllowed, body: `{"detail": "Method \"OPTIONS\" not allowed."}`},
+	} {
+		requests := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			assert.Equal(t, http.MethodOptions, r.Method)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(tc.status)
+			_, _ = w.Write([]byte(tc.body))
+		}))
+
+		ac := &client.AlpaconClient{HTTPClient: srv.Client(), BaseURL: srv.URL}
+		assert.Equal(t, tc.expected, supportsMultipart(ac), tc.name)
+		assert.Equal(t, tc.expected, supportsMultipart(ac), tc.name)
+		assert.Equal(t, 1, requests, "the answer should be cached")
+		srv.Close()
+	}
+}
@@ -1,7 +1,8 @@
 package ftp
 
 import (
-	"bytes"
+	"archive/zip"
+	"bufio"
 	"encoding/json"
 	"fmt"
 	"github.com/alpacanetworks/alpacon-cli/api/event"
@@ -10,11 +11,10 @@ import (
 	"github.com/alpacanetworks/alpacon-cli/utils"
 	"github.com/google/uuid"
 	"io"
-	"mime/multipart"
 	"net/http"
+	"os"
 	"path"
 	"path/filepath"
-	"strings"
 	"time"
 )
 
@@ -23,102 +23,265 @@ const (
 	downloadAPIURL = "/api/webftp/downloads/"
 )
 
-func uploadToS3(uploadUrl string, file io.Reader) error {
-	req, err := http.NewRequest(http.MethodPut, uploadUrl, file)
+// uploadSource describes local content to upload. open may be called more than once when a transfer is retried.
+type uploadSource struct {
+	path    string
+	name    string
+	size    int64
+	modTime time.Time
+	open    func() (io.ReadCloser, error)
+	// release frees what the source holds, such as a spooled archive or the first response of a remote object.
+	// Sources holding nothing leave it nil.
+	release func()
+}
+
+func newFileSource(filePath string) (uploadSource, error) {
+	info, err := os.Stat(filePath)
 	if err != nil {
-		return err
+		return uploadSource{}, err
+	}
+	if info.IsDir() {
+		return uploadSource{}, fmt.Errorf("%s is a directory (use -r to upload directories)", filePath)
 	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	return uploadSource{
+		path:    filePath,
+		name:    filepath.Base(filePath),
+		size:    info.Size(),
+		modTime: info.ModTime(),
+		open: func() (io.ReadCloser, error) {
+			return os.Open(filePath)
+		},
+	}, nil
+}
+
+// newFolderSource zips folderPath once into a temporary file, so the declared size is the size of the archive
+// that is uploaded even if files change meanwhile, and the folder is never held in memory.
+// The caller must call release to remove the temporary file.
+func newFolderSource(folderPath string) (uploadSource, error) {
+	info, err := os.Stat(folderPath)
 	if err != nil {
-		return err
+		return uploadSource{}, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		return err
+	archive, err := os.CreateTemp("", "alpacon-*.zip")
+	if err != nil {
+		return uploadSource{}, err
 	}
-	return nil
+	archivePath := archive.Name()
+	err = utils.Zip(archive, folderPath)
+	if closeErr := archive.Close(); err == nil {
+		err = closeErr
+	}
+	var archiveInfo os.FileInfo
+	if err == nil {
+		archiveInfo, err = os.Stat(archivePath)
+	}
+	if err != nil {
+		_ = os.Remove(archivePath)
+		return uploadSource{}, err
+	}
+
+	return uploadSource{
+		path:    folderPath,
+		name:    filepath.Base(folderPath) + ".zip",
+		size:    archiveInfo.Size(),
+		modTime: info.ModTime(),
+		open: func() (io.ReadCloser, error) {
+			return os.Open(archivePath)
+		},
+		release: func() {
+			_ = os.Remove(archivePath)
+		},
+	}, nil
 }
 
-func UploadFile(ac *client.AlpaconClient, src []string, dest, username, groupname string) ([]string, error) {
-	serverName, remotePath := utils.SplitPath(dest)
+// newRemoteSource streams the object staged at downloadURL, so it can be uploaded without touching local disk.
+// With unwrap, an archive the server staged in place of the single file name is replaced by the file it holds.
+// The first response is kept open for the initial upload attempt; retries request the object again.
+// The caller must call release once the source is no longer used.
+func newRemoteSource(downloadURL, name string, unwrap bool) (uploadSource, error) {
+	resp, err := waitForRemoteObject(downloadURL)
+	if err != nil {
+		return uploadSource{}, err
+	}
+	if resp.ContentLength < 0 {
+		_ = resp.Body.Close()
+		return uploadSource{}, fmt.Errorf("the size of %s is unknown", name)
+	}
 
-	serverID, err := server.GetServerIDByName(ac, serverName)
+	br := bufio.NewReader(resp.Body)
+	first := &remoteBody{Reader: br, Closer: resp.Body}
+	if unwrap {
+		if head, _ := br.Peek(len(zipSignature)); string(head) == zipSignature {
+			_ = first.Close()
+			wrapped, err := findWrappedFile(downloadURL, resp.ContentLength, name)
+			if err != nil {
+				return uploadSource{}, err
+			}
+			if wrapped != nil {
+				return uploadSource{
+					name:    name,
+					size:    wrapped.size,
+					modTime: wrapped.modTime,
+					open:    wrapped.open,
+					release: func() {},
+				}, nil
+			}
+			if first, err = openRemoteBody(downloadURL); err != nil {
+				return uploadSource{}, err
+			}
+		}
+	}
+
+	modTime, _ := http.ParseTime(resp.Header.Get("Last-Modified"))
+	return uploadSource{
+		name:    name,
+		size:    resp.ContentLength,
+		modTime: modTime,
+		open: func() (io.ReadCloser, error) {
+			if first != nil {
+				body := first
+				first = nil
+				return body, nil
+			}
+			return openRemoteBody(downloadURL)
+		},
+		release: func() {
+			if first != nil {
+				_ = first.Close()
+				first = nil
+			}
+		},
+	}, nil
+}
+
+type remoteBody struct {
+	io.Reader
+	io.Closer
+}
+
+func openRemoteBody(downloadURL string) (*remoteBody, error) {
+	resp, err := waitForRemoteObject(downloadURL)
 	if err != nil {
 		return nil, err
 	}
+	return &remoteBody{Reader: resp.Body, Closer: resp.Body}, nil
+}
+
+// waitForRemoteObject requests the whole object staged at downloadURL.
+func waitForRemoteObject(downloadURL string) (*http.Response, error) {
+	resp, err := waitForDownload(downloadURL, 0, "")
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("download failed with status %s", resp.Status)
+	}
+	return resp, nil
+}
 
-	var result []string
-	for _, filePath := range src {
-		file, err := utils.ReadFileFromPath(filePath)
+// uploadObject registers uploadRequest with the Alpacon server, streams src to storage,
+// and waits for the agent to place the file. It returns the command result.
+// Progress of local sources is recorded in the upload state directory, so the upload can be resumed with opts.Resume.
+func uploadObject(ac *client.AlpaconClient, uploadRequest *UploadRequest, src uploadSource, opts TransferOptions) (string, error) {
+	var stateDir, stateKey string
+	if src.path != "" {
+		var err error
+		stateDir, stateKey, err = uploadStateLocation(src.path)
 		if err != nil {
-			return nil, err
+			if opts.Resume {
+				return "", fmt.Errorf("failed to locate the upload state: %v", err)
+			}
+			// The upload still works, it just cannot be resumed later.
+			stateDir = ""
 		}
+	}
+	target := uploadRequest.Server + ":" + path.Join(uploadRequest.Path, uploadRequest.Name)
 
-		var requestBody bytes.Buffer
-		writer := multipart.NewWriter(&requestBody)
-
-		fileWriter, err := writer.CreateFormFile("content", filepath.Base(filePath))
+	var entry PartialUpload
+	resumed := false
+	if opts.Resume && stateDir != "" {
+		state, err := loadPartialState(stateDir)
 		if err != nil {
-			return nil, err
+			return "", err
 		}
-		_, err = fileWriter.Write(file)
-		if err != nil {
-			return nil, err
+		if previous, ok := state.Uploads[stateKey]; ok && previous.isResumable(target, src) {
+			entry = previous
+			resumed = true
 		}
-		_ = writer.Close()
+	}
 
-		uploadRequest := &UploadRequest{
-			Id:             uuid.New().String(),
-			Name:           filepath.Base(filePath),
-			Path:           remotePath,
-			Server:         serverID,
-			Username:       username,
-			Groupname:      groupname,
-			AllowOverwrite: "true",
+	if resumed {
+		utils.CliInfo("Resuming upload of %s.", src.name)
+	} else {
+		if src.size < 0 || src.size > multipartThreshold {
+			if supportsMultipart(ac) {
+				uploadRequest.Multipart = "true"
+			} else if src.size < 0 {
+				return "", errNoMultipart(src.name)
+			}
 		}
 
 		respBody, err := ac.SendPostRequest(uploadAPIURL, uploadRequest)
 		if err != nil {
-			return nil, err
+			return "", err
 		}
 
 		var response UploadResponse
 		err = json.Unmarshal(respBody, &response)
 		if err != nil {
-			return nil, err
+			return "", err
 		}
 
-		if response.UploadUrl != "" {
-			err = uploadToS3(response.UploadUrl, bytes.NewReader(file))
-			if err != nil {
-				return nil, err
-			}
+		entry = PartialUpload{
+			Dest:     target,
+			Size:     src.size,
+			ModTime:  src.modTime,
+			Response: response,
 		}
+		savePartialUpload(stateDir, stateKey, &entry)
+	}
 
-		fullURL := utils.BuildURL(uploadAPIURL, path.Join(response.Id, "upload"), nil)
-		_, err = ac.SendGetRequest(fullURL)
-		if err != nil {
-			return nil, err
+	response := entry.Response
+	if !entry.Uploaded {
+		var err error
+		if len(response.Parts) > 0 {
+			err = uploadParts(ac, src, &entry, stateDir, stateKey, opts.Progress)
+		} else if response.UploadUrl != "" {
+			if src.size < 0 {
+				return "", errNoMultipart(src.name)
+			}
+			err = uploadWhole(response.UploadUrl, src, opts.Progress)
 		}
-
-		status, err := event.PollCommandExecution(ac, response.Command)
 		if err != nil {
-			return nil, err
-		}
-		if status.Status["text"] == "Stuck" || status.Status["text"] == "Error" {
-			result = append(result, status.Status["message"].(string))
-		} else {
-			result = append(result, status.Result)
+			return "", err
 		}
+
+		entry.Uploaded = true
+		savePartialUpload(stateDir, stateKey, &entry)
 	}
 
-	return result, nil
+	fullURL := utils.BuildURL(uploadAPIURL, path.Join(response.Id, "upload"), nil)
+	_, err := ac.SendGetRequest(fullURL)
+	if err != nil {
+		return "", err
+	}
+
+	status, err := event.PollCommandExecution(ac, response.Command)
+	if err != nil {
+		return "", err
+	}
+	savePartialUpload(stateDir, stateKey, nil)
+
+	if status.Status["text"] == "Stuck" || status.Status["text"] == "Error" {
+		return status.Status["message"].(string), nil
+	}
+	return status.Result, nil
 }
 
-func UploadFolder(ac *client.AlpaconClient, src []string, dest, username, groupname string) ([]string, error) {
+func UploadFile(ac *client.AlpaconClient, src []string, dest string, opts TransferOptions) ([]string, error) {
 	serverName, remotePath := utils.SplitPath(dest)
 
 	serverID, err := server.GetServerIDByName(ac, serverName)
@@ -126,174 +289,310 @@ func UploadFolder(ac *client.AlpaconClient, src []string, dest, username, groupn
 		return nil, err
 	}
 
-	var result []string
-	for _, folderPath := range src {
-		zipBytes, err := utils.Zip(folderPath)
+	return runParallel(len(src), opts.Parallel, func(i int) (string, error) {
+		source, err := newFileSource(src[i])
 		if err != nil {
-			return nil, err
+			return "", err
 		}
-		zipName := filepath.Base(folderPath) + ".zip"
-
-		var requestBody bytes.Buffer
-		writer := multipart.NewWriter(&requestBody)
-
-		fileWriter, err := writer.CreateFormFile("content", zipName)
-		if err != nil {
-			return nil, err
-		}
-		_, err = fileWriter.Write(zipBytes)
-		if err != nil {
-			return nil, err
-		}
-		_ = writer.Close()
 
 		uploadRequest := &UploadRequest{
 			Id:             uuid.New().String(),
-			AllowUnzip:     "true",
-			AllowOverwrite: "true",
-			Name:           zipName,
+			Name:           source.name,
 			Path:           remotePath,
 			Server:         serverID,
-			Username:       username,
-			Groupname:      groupname,
+			Username:       opts.Username,
+			Groupname:      opts.Groupname,
+			AllowOverwrite: "true",
 		}
 
-		respBody, err := ac.SendPostRequest(uploadAPIURL, uploadRequest)
-		if err != nil {
-			return nil, err
+		result, err := uploadObject(ac, uploadRequest, source, opts)
+		if err != nil || !opts.Verify {
+			return result, err
 		}
+		return result, verifyTransfer(ac, serverName, path.Join(remotePath, source.name), src[i], opts)
+	})
+}
 
-		var response UploadResponse
-		err = json.Unmarshal(respBody, &response)
-		if err != nil {
-			return nil, err
-		}
+func UploadFolder(ac *client.AlpaconClient, src []string, dest string, opts TransferOptions) ([]string, error) {
+	serverName, remotePath := utils.SplitPath(dest)
 
-		if response.UploadUrl != "" {
-			err = uploadToS3(response.UploadUrl, bytes.NewReader(zipBytes))
-			if err != nil {
-				return nil, err
-			}
-		}
+	serverID, err := server.GetServerIDByName(ac, serverName)
+	if err != nil {
+		return nil, err
+	}
 
-		_, err = ac.SendGetRequest(uploadAPIURL + response.Id + "/upload")
+	return runParallel(len(src), opts.Parallel, func(i int) (string, error) {
+		source, err := newFolderSource(src[i])
 		if err != nil {
-			return nil, err
+			return "", err
 		}
+		defer source.release()
 
-		status, err := event.PollCommandExecution(ac, response.Command)
-		if err != nil {
-			return nil, err
-		}
-		if status.Status["text"] == "Stuck" || status.Status["text"] == "Error" {
-			result = append(result, status.Status["message"].(string))
-		} else {
-			result = append(result, status.Result)
+		uploadRequest := &UploadRequest{
+			Id:             uuid.New().String(),
+			AllowUnzip:     "true",
+			AllowOverwrite: "true",
+			Name:           source.name,
+			Path:           remotePath,
+			Server:         serverID,
+			Username:       opts.Username,
+			Groupname:      opts.Groupname,
 		}
-	}
 
-	return result, nil
+		result, err := uploadObject(ac, uploadRequest, source, opts)
+		if err != nil || !opts.Verify {
+			return result, err
+		}
+		return result, verifyTransfer(ac, serverName, path.Join(remotePath, filepath.Base(src[i])), src[i], opts)
+	})
 }
 
-func DownloadFile(ac *client.AlpaconClient, src, dest, username, groupname string, recursive bool) error {
+func DownloadFile(ac *client.AlpaconClient, src, dest string, opts TransferOptions) error {
 	serverName, remotePathStr := utils.SplitPath(src)
 
-	var remotePaths []string
 	var resourceType string
 
-	trimmedPathStr := strings.Trim(remotePathStr, "\"")
-	remotePaths = strings.Fields(trimmedPathStr)
-
 	serverID, err := server.GetServerIDByName(ac, serverName)
 	if err != nil {
 		return err
 	}
 
-	if recursive {
+	remotePaths, err := expandRemotePaths(ac, serverName, remotePathStr, opts)
+	if err != nil {
+		return err
+	}
+
+	if opts.Recursive {
 		resourceType = "folder"
 	} else {
 		resourceType = "file"
 	}
 
-	for _, path := range remotePaths {
-		downloadRequest := &DownloadRequest{
-			Path:         path,
-			Name:         filepath.Base(path),
-			Server:       serverID,
-			Username:     username,
-			Groupname:    groupname,
-			ResourceType: resourceType,
-		}
+	_, err = runParallel(len(remotePaths), opts.Parallel, func(i int) (string, error) {
+		return "", downloadObject(ac, serverID, serverName, remotePaths[i], resourceType, dest, opts)
+	})
+	return err
+}
 
-		postBody, err := ac.SendPostRequest(downloadAPIURL, downloadRequest)
+// downloadObject asks the agent to stage remotePath in storage and saves it below dest.
+func downloadObject(ac *client.AlpaconClient, serverID, serverName, remotePath, resourceType, dest string, opts TransferOptions) error {
+	downloadResponse, err := stageDownload(ac, serverID, remotePath, resourceType, opts)
+	if err != nil {
+		return err
+	}
+
+	isFolder := resourceType == "folder"
+	fileName := filepath.Base(remotePath)
+	if isFolder {
+		fileName += ".zip"
+	}
+
+	localPath := filepath.Join(dest, fileName)
+	err = downloadToFile(downloadResponse.DownloadURL, localPath, serverName+":"+remotePath, opts)
+	if err != nil {
+		return err
+	}
+
+	err = extractDownload(localPath, dest, filepath.Base(remotePath), isFolder)
+	if err != nil {
+		return err
+	}
+
+	if opts.Verify {
+		return verifyTransfer(ac, serverName, remotePath, filepath.Join(dest, filepath.Base(remotePath)), opts)
+	}
+	return nil
+}
+
+// CopyRemote copies the remote paths in src into the directory dest on another (or the same) server.
+// Each object is streamed from the download URL of its source into the upload URL of the destination
+// through the CLI, so it is never written to local disk.
+func CopyRemote(ac *client.AlpaconClient, src []string, dest string, opts TransferOptions) ([]string, error) {
+	destServerName, destPath := utils.SplitPath(dest)
+	destServerID, err := server.GetServerIDByName(ac, destServerName)
+	if err != nil {
+		return nil, err
+	}
+
+	type remoteObject struct {
+		serverName string
+		serverID   string
+		path       string
+	}
+	var objects []remoteObject
+	for _, s := range src {
+		serverName, remotePathStr := utils.SplitPath(s)
+		serverID, err := server.GetServerIDByName(ac, serverName)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		remotePaths, err := expandRemotePaths(ac, serverName, remotePathStr, opts)
+		if err != nil {
+			return nil, err
 		}
+		for _, remotePath := range remotePaths {
+			objects = append(objects, remoteObject{serverName: serverName, serverID: serverID, path: remotePath})
+		}
+	}
 
-		var downloadResponse DownloadResponse
-		err = json.Unmarshal(postBody, &downloadResponse)
+	resourceType := "file"
+	if opts.Recursive {
+		resourceType = "folder"
+	}
+
+	return runParallel(len(objects), opts.Parallel, func(i int) (string, error) {
+		object := objects[i]
+		downloadResponse, err := stageDownload(ac, object.serverID, object.path, resourceType, opts)
 		if err != nil {
-			return err
+			return "", err
 		}
 
-		status, err := event.PollCommandExecution(ac, downloadResponse.Command)
+		// Folders are staged as archives, which the destination agent unpacks again.
+		name := path.Base(object.path)
+		if opts.Recursive {
+			name += ".zip"
+		}
+		source, err := newRemoteSource(downloadResponse.DownloadURL, name, !opts.Recursive)
 		if err != nil {
-			return err
+			return "", fmt.Errorf("%s: %v", object.path, err)
 		}
+		defer source.release()
 
-		if status.Status["text"] == "Stuck" || status.Status["text"] == "Error" {
-			utils.CliError("%s", status.Status["message"].(string))
+		uploadRequest := &UploadRequest{
+			Id:             uuid.New().String(),
+			Name:           name,
+			Path:           destPath,
+			Server:         destServerID,
+			Username:       opts.Username,
+			Groupname:      opts.Groupname,
+			AllowOverwrite: "true",
 		}
-		if status.Status["text"] == "Failed" {
-			utils.CliError("%s", status.Result)
+		if opts.Recursive {
+			uploadRequest.AllowUnzip = "true"
 		}
-		utils.CliWarning("File Transfer Status: '%s'. Attempting to transfer '%s' from the Alpacon server. Note: Transfer may timeout after 100 seconds.", status.Result, path)
 
-		maxAttempts := 100
-		var resp *http.Response
-		for count := 0; count < maxAttempts; count++ {
-			resp, err = http.Get(downloadResponse.DownloadURL)
-			if err != nil {
-				return err
-			}
+		result, err := uploadObject(ac, uploadRequest, source, opts)
+		if err != nil || !opts.Verify {
+			return result, err
+		}
+		copyPath := path.Join(destPath, path.Base(object.path))
+		return result, verifyRemoteCopy(ac, object.serverName, object.path, destServerName, copyPath, opts.Recursive, opts)
+	})
+}
 
-			if resp.StatusCode == http.StatusOK {
-				break
-			} else {
-				time.Sleep(time.Second * 1)
-			}
+// stageDownload asks the agent on serverID to upload remotePath to storage and waits until it has done so.
+func stageDownload(ac *client.AlpaconClient, serverID, remotePath, resourceType string, opts TransferOptions) (DownloadResponse, error) {
+	downloadRequest := &DownloadRequest{
+		Path:         remotePath,
+		Name:         filepath.Base(remotePath),
+		Server:       serverID,
+		Username:     opts.Username,
+		Groupname:    opts.Groupname,
+		ResourceType: resourceType,
+	}
 
-			if count == maxAttempts-1 {
-				return fmt.Errorf("%d attempts", maxAttempts)
-			}
-		}
+	postBody, err := ac.SendPostRequest(downloadAPIURL, downloadRequest)
+	if err != nil {
+		return DownloadResponse{}, err
+	}
+
+	var downloadResponse DownloadResponse
+	err = json.Unmarshal(postBody, &downloadResponse)
+	if err != nil {
+		return DownloadResponse{}, err
+	}
 
-		defer func() { _ = resp.Body.Close() }()
+	status, err := event.PollCommandExecution(ac, downloadResponse.Command)
+	if err != nil {
+		return DownloadResponse{}, err
+	}
 
-		respBody, err := io.ReadAll(resp.Body)
-		if err != nil {
+	if status.Status["text"] == "Stuck" || status.Status["text"] == "Error" {
+		return DownloadResponse{}, fmt.Errorf("%s: %s", remotePath, status.Status["message"])
+	}
+	if status.Status["text"] == "Failed" {
+		return DownloadResponse{}, fmt.Errorf("%s: %s", remotePath, status.Result)
+	}
+	utils.CliWarning("File Transfer Status: '%s'. Attempting to transfer '%s' from the Alpacon server. Note: Transfer may timeout after 100 seconds.", status.Result, remotePath)
+	return downloadResponse, nil
+}
+
+// extractDownload unpacks the archive the server returned for a download into dest.
+// Folders always arrive as archives. A single file is only unpacked when the server wrapped it
+// in an archive holding just that file; any other file is kept exactly as downloaded.
+func extractDownload(localPath, dest, name string, isFolder bool) error {
+	isZip, err := utils.IsZipFile(localPath)
+	if err != nil {
+		return err
+	}
+
+	if isFolder {
+		if !isZip {
+			return fmt.Errorf("the server did not return an archive for the folder %s", name)
+		}
+	} else {
+		if !isZip {
+			return nil
+		}
+		wrapped, err := isWrappedFile(localPath, name)
+		if err != nil || !wrapped {
 			return err
 		}
 
-		var fileName string
-		if recursive {
-			fileName = filepath.Base(path) + ".zip"
-		} else {
-			fileName = filepath.Base(path)
-		}
-		err = utils.SaveFile(filepath.Join(dest, fileName), respBody)
-		if err != nil {
+		// The archive has the same name as the file it holds, so move it aside before extracting.
+		archivePath := localPath + partialSuffix + ".zip"
+		if err = os.Rename(localPath, archivePath); err != nil {
 			return err
 		}
-		err = utils.Unzip(filepath.Join(dest, fileName), dest)
+		localPath = archivePath
+	}
+
+	if err = utils.Unzip(localPath, dest); err != nil {
+		return err
+	}
+	return utils.DeleteFile(localPath)
+}
+
+// isWrappedFile reports whether the archive at archivePath consists of the single regular file name.
+func isWrappedFile(archivePath, name string) (bool, error) {
+	r, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = r.Close() }()
+
+	return len(r.File) == 1 && r.File[0].Name == name && r.File[0].Mode().IsRegular(), nil
+}
+
+// waitForDownload polls downloadURL until the agent has finished uploading the object to storage.
+// When offset is positive, only the remainder of the object is requested; etag guards against the object having changed.
+// The caller must close the body of the returned response.
+func waitForDownload(downloadURL string, offset int64, etag string) (*http.Response, error) {
+	maxAttempts := 100
+	for count := 0; count < maxAttempts; count++ {
+		req, err := http.NewRequest(http.MethodGet, downloadURL, nil)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		if offset > 0 {
+			req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+			if etag != "" {
+				req.Header.Set("If-Range", etag)
+			}
 		}
-		err = utils.DeleteFile(filepath.Join(dest, fileName))
+
+		resp, err := http.DefaultClient.Do(req)
 		if err != nil {
-			return err
+			return nil, err
 		}
+
+		switch resp.StatusCode {
+		case http.StatusOK, http.StatusPartialContent, http.StatusRequestedRangeNotSatisfiable:
+			return resp, nil
+		}
+		_ = resp.Body.Close()
+		time.Sleep(time.Second * 1)
 	}
 
-	return nil
+	return nil, fmt.Errorf("%d attempts", maxAttempts)
 }
@@ -0,0 +1,39 @@
+package ftp
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFolderSourceUploadsTheMeasuredArchive(t *testing.T) {
+	folder := filepath.Join(t.TempDir(), "site")
+	assert.NoError(t, os.Mkdir(folder, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(folder, "index.html"), []byte("<html></html>"), 0644))
+
+	src, err := newFolderSource(folder)
+	assert.NoError(t, err)
+	assert.Equal(t, "site.zip", src.name)
+
+	// Changes after the archive was built must not make the body disagree with the declared size.
+	assert.NoError(t, os.WriteFile(filepath.Join(folder, "index.html"), []byte("<html><body>changed</body></html>"), 0644))
+
+	for attempt := 0; attempt < 2; attempt++ {
+		body, err := src.open()
+		assert.NoError(t, err)
+		content, err := io.ReadAll(body)
+		assert.NoError(t, err)
+		assert.NoError(t, body.Close())
+		assert.Equal(t, src.size, int64(len(content)))
+	}
+
+	body, err := src.open()
+	assert.NoError(t, err)
+	archivePath := body.(*os.File).Name()
+	assert.NoError(t, body.Close())
+	src.release()
+	_, err = os.Stat(archivePath)
+	assert.True(t, os.IsNotExist(err), "release should remove the archive")
+}
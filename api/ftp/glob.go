@@ -0,0 +1,203 @@
+package ftp
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/alpacanetworks/alpacon-cli/api/event"
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"strconv"
+	"strings"
+)
+
+// globMarker precedes the matches of each pattern in the output of the expansion command.
+const globMarker = "__alpacon_glob__"
+
+// remoteWord is one path of a remote argument after quote removal.
+type remoteWord struct {
+	// path is the literal path, used when the word has no wildcards.
+	path string
+	// pattern is the word requoted for the remote shell, with only its wildcards left unquoted.
+	pattern string
+	// glob reports whether the word has unquoted wildcards (*, ? or [).
+	glob bool
+}
+
+// expandRemotePaths splits the path part of a remote argument into paths and resolves wildcards on serverName.
+// Words are split like in a POSIX shell: whitespace separates paths, and quotes or backslashes keep spaces
+// and wildcards literal. Every pattern must match at least one file.
+func expandRemotePaths(ac *client.AlpaconClient, serverName, remotePathStr string, opts TransferOptions) ([]string, error) {
+	words, err := splitRemoteWords(remotePathStr)
+	if err != nil {
+		return nil, err
+	}
+	if len(words) == 0 {
+		return nil, errors.New("no remote path given")
+	}
+
+	var patterns []string
+	for _, word := range words {
+		if word.glob {
+			patterns = append(patterns, word.pattern)
+		}
+	}
+	if len(patterns) == 0 {
+		var paths []string
+		for _, word := range words {
+			paths = append(paths, word.path)
+		}
+		return paths, nil
+	}
+
+	var command strings.Builder
+	for i, pattern := range patterns {
+		fmt.Fprintf(&command, `echo %s%d; for f in %s; do if [ -e "$f" ] || [ -L "$f" ]; then printf '%%s\n' "$f"; fi; done; `,
+			globMarker, i, pattern)
+	}
+	output, err := event.RunCommand(ac, serverName, strings.TrimSuffix(command.String(), " "), opts.Username, opts.Groupname, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := parseGlobOutput(output, len(patterns))
+	if err != nil {
+		return nil, err
+	}
+
+	var paths, unmatched []string
+	i := 0
+	for _, word := range words {
+		if !word.glob {
+			paths = append(paths, word.path)
+			continue
+		}
+		if len(matches[i]) == 0 {
+			unmatched = append(unmatched, fmt.Sprintf("no match for %s on %s", word.path, serverName))
+		}
+		paths = append(paths, matches[i]...)
+		i++
+	}
+	if len(unmatched) > 0 {
+		return nil, errors.New(strings.Join(unmatched, "; "))
+	}
+	return paths, nil
+}
+
+// parseGlobOutput groups the lines printed by the expansion command by pattern.
+func parseGlobOutput(output string, count int) ([][]string, error) {
+	matches := make([][]string, count)
+	current := -1
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, globMarker) {
+			i, err := strconv.Atoi(strings.TrimPrefix(line, globMarker))
+			if err != nil || i < 0 || i >= count {
+				return nil, fmt.Errorf("unexpected output while expanding remote paths: %q", line)
+			}
+			current = i
+			continue
+		}
+		if line == "" {
+			continue
+		}
+		if current < 0 {
+			return nil, fmt.Errorf("failed to expand remote paths: %s", strings.TrimSpace(output))
+		}
+		matches[current] = append(matches[current], line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if current != count-1 {
+		return nil, fmt.Errorf("failed to expand remote paths: %s", strings.TrimSpace(output))
+	}
+	return matches, nil
+}
+
+// splitRemoteWords splits s into words using POSIX shell quoting rules.
+// Single quotes keep everything literal, double quotes keep everything but \", \\, \$ and \` literal,
+// and a backslash outside quotes escapes the next character.
+func splitRemoteWords(s string) ([]remoteWord, error) {
+	var words []remoteWord
+	var word remoteWord
+	var path, pattern, literal strings.Builder
+	inWord := false
+
+	// flushLiteral appends the pending quoted characters to the pattern.
+	flushLiteral := func() {
+		if literal.Len() > 0 {
+			pattern.WriteString(utils.ShellQuote(literal.String()))
+			literal.Reset()
+		}
+	}
+	endWord := func() {
+		if !inWord {
+			return
+		}
+		flushLiteral()
+		word.path, word.pattern = path.String(), pattern.String()
+		words = append(words, word)
+		word = remoteWord{}
+		path.Reset()
+		pattern.Reset()
+		inWord = false
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			endWord()
+		case c == '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated single quote in %q", s)
+			}
+			path.WriteString(s[i+1 : i+1+end])
+			literal.WriteString(s[i+1 : i+1+end])
+			inWord = true
+			i += end + 1
+		case c == '"':
+			i++
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) && strings.IndexByte("\"\\$`", s[i+1]) >= 0 {
+					i++
+				}
+				path.WriteByte(s[i])
+				literal.WriteByte(s[i])
+			}
+			if i >= len(s) {
+				return nil, fmt.Errorf("unterminated double quote in %q", s)
+			}
+			inWord = true
+		case c == '\\':
+			if i+1 < len(s) {
+				i++
+				path.WriteByte(s[i])
+				literal.WriteByte(s[i])
+			}
+			inWord = true
+		case c == '*' || c == '?' || c == '[':
+			flushLiteral()
+			path.WriteByte(c)
+			pattern.WriteByte(c)
+			word.glob = true
+			inWord = true
+		case c == ']' || c == '!' || c == '^' || c == '-':
+			// These only have a meaning inside a bracket expression and must stay unquoted there.
+			flushLiteral()
+			path.WriteByte(c)
+			pattern.WriteByte(c)
+			inWord = true
+		default:
+			path.WriteByte(c)
+			literal.WriteByte(c)
+			inWord = true
+		}
+	}
+	endWord()
+	return words, nil
+}
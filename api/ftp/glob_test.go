@@ -0,0 +1,35 @@
+package ftp
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSplitRemoteWords(t *testing.T) {
+	words, err := splitRemoteWords(`/var/log/*.log "/srv/my app/conf" /data/report\ 2024.csv '/tmp/[x].txt' /backup/db-[0-9].gz`)
+	assert.NoError(t, err)
+	assert.Equal(t, []remoteWord{
+		{path: "/var/log/*.log", pattern: `'/var/log/'*'.log'`, glob: true},
+		{path: "/srv/my app/conf", pattern: `'/srv/my app/conf'`},
+		{path: "/data/report 2024.csv", pattern: `'/data/report 2024.csv'`},
+		{path: "/tmp/[x].txt", pattern: `'/tmp/[x].txt'`},
+		{path: "/backup/db-[0-9].gz", pattern: `'/backup/db'-['0'-'9']'.gz'`, glob: true},
+	}, words)
+}
+
+func TestSplitRemoteWordsRejectsUnterminatedQuotes(t *testing.T) {
+	_, err := splitRemoteWords(`"/srv/my app`)
+	assert.Error(t, err)
+	_, err = splitRemoteWords(`'/srv/my app`)
+	assert.Error(t, err)
+}
+
+func TestParseGlobOutput(t *testing.T) {
+	output := "__alpacon_glob__0\n/var/log/a.log\n/var/log/b.log\n__alpacon_glob__1\n"
+	matches, err := parseGlobOutput(output, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"/var/log/a.log", "/var/log/b.log"}, nil}, matches)
+
+	_, err = parseGlobOutput("sh: 1: Syntax error\n", 1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,57 @@
+package ftp
+
+import "sync"
+
+// runParallel calls fn for every index in [0, n) using at most parallel concurrent workers.
+// Results are returned in index order. After the first failure no new work is started,
+// and the error of the lowest failing index is returned once in-flight calls have finished.
+func runParallel(n, parallel int, fn func(i int) (string, error)) ([]string, error) {
+	if parallel < 1 {
+		parallel = 1
+	}
+	if parallel > n {
+		parallel = n
+	}
+
+	results := make([]string, n)
+	errs := make([]error, n)
+	jobs := make(chan int)
+
+	var mu sync.Mutex
+	failed := false
+
+	var wg sync.WaitGroup
+	for w := 0; w < parallel; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i], errs[i] = fn(i)
+				if errs[i] != nil {
+					mu.Lock()
+					failed = true
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		mu.Lock()
+		stop := failed
+		mu.Unlock()
+		if stop {
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
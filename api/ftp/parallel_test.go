@@ -0,0 +1,44 @@
+package ftp
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunParallelKeepsInputOrder(t *testing.T) {
+	var running, peak int32
+
+	results, err := runParallel(10, 4, func(i int) (string, error) {
+		current := atomic.AddInt32(&running, 1)
+		for {
+			old := atomic.LoadInt32(&peak)
+			if current <= old || atomic.CompareAndSwapInt32(&peak, old, current) {
+				break
+			}
+		}
+		// Later items finish first to make out-of-order completion likely.
+		time.Sleep(time.Duration(10-i) * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return fmt.Sprintf("file-%d", i), nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"file-0", "file-1", "file-2", "file-3", "file-4", "file-5", "file-6", "file-7", "file-8", "file-9"}, results)
+	assert.LessOrEqual(t, peak, int32(4))
+}
+
+func TestRunParallelReturnsFirstError(t *testing.T) {
+	results, err := runParallel(5, 1, func(i int) (string, error) {
+		if i >= 2 {
+			return "", fmt.Errorf("file-%d failed", i)
+		}
+		return "ok", nil
+	})
+
+	assert.Nil(t, results)
+	assert.Equal(t, errors.New("file-2 failed"), err)
+}
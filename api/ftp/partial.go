@@ -0,0 +1,102 @@
+package ftp
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/alpacanetworks/alpacon-cli/config"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	// partialStateFile records interrupted transfers in the directory holding their state.
+	partialStateFile = ".alpacon-transfers.json"
+	// partialSuffix is appended to files that are still being downloaded.
+	partialSuffix = ".alpacon-partial"
+	// uploadStateDir holds the state of interrupted uploads under the config directory,
+	// so that uploading never writes into the source directory.
+	uploadStateDir = "transfers"
+)
+
+// uploadStateLocation returns the directory recording interrupted uploads and the key of localPath in it.
+func uploadStateLocation(localPath string) (string, string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", "", err
+	}
+
+	stateDir := filepath.Join(homeDir, config.ConfigFileDir, uploadStateDir)
+	if err = os.MkdirAll(stateDir, 0700); err != nil {
+		return "", "", err
+	}
+
+	absPath, err := filepath.Abs(localPath)
+	if err != nil {
+		return "", "", err
+	}
+	return stateDir, absPath, nil
+}
+
+func loadPartialState(dir string) (PartialState, error) {
+	var state PartialState
+
+	content, err := os.ReadFile(filepath.Join(dir, partialStateFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return state, err
+	}
+
+	if err = json.Unmarshal(content, &state); err != nil {
+		return state, fmt.Errorf("failed to decode %s: %v", partialStateFile, err)
+	}
+	return state, nil
+}
+
+func savePartialState(dir string, state PartialState) error {
+	statePath := filepath.Join(dir, partialStateFile)
+	if len(state.Uploads) == 0 && len(state.Downloads) == 0 {
+		err := os.Remove(statePath)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	content, err := json.MarshalIndent(state, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so an interruption never leaves a truncated state file.
+	tmpPath := statePath + ".tmp"
+	if err = os.WriteFile(tmpPath, content, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, statePath)
+}
+
+var partialStateMu sync.Mutex
+
+// updatePartialState loads the state of dir, applies fn and saves the result.
+func updatePartialState(dir string, fn func(state *PartialState)) error {
+	partialStateMu.Lock()
+	defer partialStateMu.Unlock()
+
+	state, err := loadPartialState(dir)
+	if err != nil {
+		return err
+	}
+
+	if state.Uploads == nil {
+		state.Uploads = map[string]PartialUpload{}
+	}
+	if state.Downloads == nil {
+		state.Downloads = map[string]PartialDownload{}
+	}
+	fn(&state)
+
+	return savePartialState(dir, state)
+}
@@ -0,0 +1,363 @@
+package ftp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// multipartThreshold is the size above which servers that support it are asked for a multipart upload.
+	multipartThreshold = 64 * 1024 * 1024
+	// maxTransferAttempts bounds how often a single PUT, part or download is retried after a dropped connection.
+	maxTransferAttempts = 5
+)
+
+// uploadToS3 streams file to the presigned uploadUrl and returns the ETag of the stored object.
+// size must match the number of bytes file yields, as S3 rejects PUT requests without a Content-Length.
+func uploadToS3(uploadUrl string, file io.Reader, size int64) (string, error) {
+	req, err := http.NewRequest(http.MethodPut, uploadUrl, file)
+	if err != nil {
+		return "", err
+	}
+	req.ContentLength = size
+	if size == 0 {
+		req.Body = http.NoBody
+	}
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		err = fmt.Errorf("upload failed with status %s", resp.Status)
+		if resp.StatusCode < http.StatusInternalServerError {
+			// Client errors such as an expired URL will not go away by retrying.
+			return "", permanentError{err}
+		}
+		return "", err
+	}
+	return resp.Header.Get("ETag"), nil
+}
+
+// uploadWhole sends src with a single PUT request, reopening it for each retry.
+func uploadWhole(uploadUrl string, src uploadSource, progress *utils.Progress) error {
+	transfer := progress.Start(src.name, src.size)
+	defer transfer.Finish()
+
+	return retryTransfer(src.name, func() error {
+		transfer.SetCurrent(0)
+
+		content, err := src.open()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = content.Close() }()
+
+		_, err = uploadToS3(uploadUrl, transfer.Reader(content), src.size)
+		return err
+	})
+}
+
+// uploadParts sends src in the parts announced by the server, skipping parts recorded as completed in entry.
+// Only one part is held in memory at a time. A source of unknown size (negative src.size) is read until EOF.
+func uploadParts(ac *client.AlpaconClient, src uploadSource, entry *PartialUpload, stateDir, stateKey string, progress *utils.Progress) error {
+	response := entry.Response
+	if response.PartSize <= 0 {
+		return errors.New("server announced a multipart upload without a part size")
+	}
+
+	completed := make(map[int]bool)
+	for _, part := range entry.Completed {
+		completed[part.PartNumber] = true
+	}
+
+	content, err := src.open()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = content.Close() }()
+
+	transfer := progress.Start(src.name, src.size)
+	defer transfer.Finish()
+
+	buf := make([]byte, response.PartSize)
+	var offset int64
+	exhausted := false
+	for _, part := range response.Parts {
+		n := response.PartSize
+		if remaining := src.size - offset; src.size >= 0 && remaining < n {
+			n = remaining
+		}
+		if n <= 0 {
+			exhausted = true
+			break
+		}
+
+		read, err := io.ReadFull(content, buf[:n])
+		if src.size < 0 && (err == io.EOF || err == io.ErrUnexpectedEOF) {
+			// A source of unknown size ends with its last, possibly short, part.
+			n, err = int64(read), nil
+			exhausted = true
+			if n == 0 {
+				break
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read part %d of %s: %v", part.PartNumber, src.name, err)
+		}
+		offset += n
+
+		if completed[part.PartNumber] {
+			transfer.Add(n)
+			continue
+		}
+
+		start := transfer.Current()
+		var etag string
+		err = retryTransfer(fmt.Sprintf("%s (part %d)", src.name, part.PartNumber), func() error {
+			transfer.SetCurrent(start)
+			etag, err = uploadToS3(part.UploadUrl, transfer.Reader(bytes.NewReader(buf[:n])), n)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		entry.Completed = append(entry.Completed, CompletedPart{PartNumber: part.PartNumber, ETag: etag})
+		savePartialUpload(stateDir, stateKey, entry)
+		if exhausted {
+			break
+		}
+	}
+	if src.size < 0 && !exhausted {
+		var probe [1]byte
+		if n, _ := io.ReadFull(content, probe[:]); n > 0 {
+			return fmt.Errorf("%s is larger than the %d parts of %s the server accepts", src.name, len(response.Parts), utils.FormatBytes(response.PartSize))
+		}
+	}
+
+	completeURL := utils.BuildURL(uploadAPIURL, path.Join(response.Id, "complete"), nil)
+	_, err = ac.SendPostRequest(completeURL, &CompleteUploadRequest{Parts: entry.Completed})
+	return err
+}
+
+// downloadToFile streams downloadURL into localPath through a partial file.
+// Dropped connections are resumed with HTTP Range requests, and the partial state survives the process
+// so that a later run with opts.Resume continues where this one stopped.
+func downloadToFile(downloadURL, localPath, source string, opts TransferOptions) error {
+	stateDir, stateKey := partialLocation(localPath)
+	partialPath := localPath + partialSuffix
+
+	var offset int64
+	var etag string
+	if opts.Resume {
+		state, err := loadPartialState(stateDir)
+		if err != nil {
+			return err
+		}
+		if previous, ok := state.Downloads[stateKey]; ok && previous.Source == source {
+			if info, err := os.Stat(partialPath); err == nil {
+				offset = info.Size()
+				etag = previous.ETag
+				utils.CliInfo("Resuming download of %s at %s.", filepath.Base(localPath), utils.FormatBytes(offset))
+			}
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(localPath), 0755); err != nil {
+		return fmt.Errorf("failed to create directories: %v", err)
+	}
+
+	transfer := opts.Progress.Start(filepath.Base(localPath), -1)
+	defer transfer.Finish()
+	transfer.SetCurrent(offset)
+
+	err := retryTransfer(filepath.Base(localPath), func() error {
+		resp, err := waitForDownload(downloadURL, offset, etag)
+		if err != nil {
+			return permanentError{err}
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		flags := os.O_WRONLY | os.O_CREATE
+		switch resp.StatusCode {
+		case http.StatusRequestedRangeNotSatisfiable:
+			// The partial file already holds the whole object.
+			return nil
+		case http.StatusPartialContent:
+			flags |= os.O_APPEND
+			transfer.SetTotal(offset + resp.ContentLength)
+		default:
+			// The range was ignored or the object changed, so start over.
+			offset = 0
+			flags |= os.O_TRUNC
+			transfer.SetTotal(resp.ContentLength)
+		}
+		transfer.SetCurrent(offset)
+
+		if newETag := resp.Header.Get("ETag"); newETag != "" {
+			etag = newETag
+		}
+		savePartialDownload(stateDir, stateKey, &PartialDownload{
+			Source: source,
+			ETag:   etag,
+			Size:   offset + resp.ContentLength,
+			Added:  time.Now(),
+		})
+
+		file, err := os.OpenFile(partialPath, flags, 0644)
+		if err != nil {
+			return err
+		}
+		n, err := io.Copy(file, transfer.Reader(resp.Body))
+		offset += n
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = os.Rename(partialPath, localPath); err != nil {
+		return err
+	}
+	savePartialDownload(stateDir, stateKey, nil)
+	return nil
+}
+
+// downloadToWriter streams downloadURL into w. Dropped connections are resumed with HTTP Range requests.
+// Data already written cannot be taken back, so the transfer fails if the object changes in between.
+func downloadToWriter(downloadURL string, w io.Writer, name string, progress *utils.Progress) error {
+	transfer := progress.Start(name, -1)
+	defer transfer.Finish()
+
+	var offset int64
+	var etag string
+	return retryTransfer(name, func() error {
+		resp, err := waitForDownload(downloadURL, offset, etag)
+		if err != nil {
+			return permanentError{err}
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		switch resp.StatusCode {
+		case http.StatusRequestedRangeNotSatisfiable:
+			// Everything has been written already.
+			return nil
+		case http.StatusPartialContent:
+		default:
+			if offset > 0 {
+				return permanentError{fmt.Errorf("the object changed while it was being read")}
+			}
+			transfer.SetTotal(resp.ContentLength)
+		}
+
+		if etag == "" {
+			etag = resp.Header.Get("ETag")
+		}
+		n, err := io.Copy(permanentWriter{w}, transfer.Reader(resp.Body))
+		offset += n
+		return err
+	})
+}
+
+// permanentWriter marks write errors as permanent, as retrying cannot fix a closed pipe.
+type permanentWriter struct {
+	io.Writer
+}
+
+func (w permanentWriter) Write(p []byte) (int, error) {
+	n, err := w.Writer.Write(p)
+	if err != nil {
+		err = permanentError{err}
+	}
+	return n, err
+}
+
+// permanentError marks a failure that retryTransfer must not retry.
+type permanentError struct {
+	error
+}
+
+// retryTransfer runs fn until it succeeds or maxTransferAttempts is reached, backing off between attempts.
+func retryTransfer(name string, fn func() error) error {
+	var err error
+	for attempt := 1; attempt <= maxTransferAttempts; attempt++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+		var permanent permanentError
+		if errors.As(err, &permanent) {
+			return fmt.Errorf("%s: %v", name, permanent.error)
+		}
+		if attempt < maxTransferAttempts {
+			utils.CliWarning("Transfer of %s interrupted: %s. Retrying (%d/%d).", name, err, attempt, maxTransferAttempts-1)
+			time.Sleep(time.Duration(attempt) * time.Second)
+		}
+	}
+	return fmt.Errorf("%s: %v (run again with --resume to continue)", name, err)
+}
+
+// partialLocation returns the directory holding the partial state for localPath and the key of its entry.
+func partialLocation(localPath string) (string, string) {
+	absPath, err := filepath.Abs(localPath)
+	if err != nil {
+		absPath = localPath
+	}
+	return filepath.Dir(absPath), filepath.Base(absPath)
+}
+
+func (p PartialUpload) isResumable(target string, src uploadSource) bool {
+	if p.Dest != target || p.Size != src.size || !p.ModTime.Equal(src.modTime) {
+		return false
+	}
+	// Presigned URLs cannot be reused once they have expired.
+	return p.Uploaded || p.Response.ExpiresAt.IsZero() || time.Now().Before(p.Response.ExpiresAt)
+}
+
+// savePartialUpload records entry under key, or removes the key when entry is nil.
+// Uploads without a local source have no state directory and are not recorded.
+// Failing to persist the state only affects --resume, so it is reported as a warning.
+func savePartialUpload(dir, key string, entry *PartialUpload) {
+	if dir == "" {
+		return
+	}
+	err := updatePartialState(dir, func(state *PartialState) {
+		if entry == nil {
+			delete(state.Uploads, key)
+		} else {
+			state.Uploads[key] = *entry
+		}
+	})
+	if err != nil {
+		utils.CliWarning("Failed to update %s: %s.", filepath.Join(dir, partialStateFile), err)
+	}
+}
+
+// savePartialDownload records entry under key, or removes the key when entry is nil.
+func savePartialDownload(dir, key string, entry *PartialDownload) {
+	err := updatePartialState(dir, func(state *PartialState) {
+		if entry == nil {
+			delete(state.Downloads, key)
+		} else {
+			state.Downloads[key] = *entry
+		}
+	})
+	if err != nil {
+		utils.CliWarning("Failed to update %s: %s.", filepath.Join(dir, partialStateFile), err)
+	}
+}
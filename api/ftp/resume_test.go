@@ -0,0 +1,134 @@
+package ftp
+
+import (
+	"archive/zip"
+	"bytes"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDownloadToFileResumesDroppedConnection(t *testing.T) {
+	data := bytes.Repeat([]byte("alpacon!"), 64*1024)
+	var ranges []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"etag"`)
+		if len(ranges) == 0 {
+			ranges = append(ranges, r.Header.Get("Range"))
+			// Send the first third of the object and drop the connection.
+			w.Header().Set("Content-Length", "524288")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(data[:len(data)/3])
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			_ = conn.Close()
+			return
+		}
+		ranges = append(ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "object", time.Time{}, bytes.NewReader(data))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	localPath := filepath.Join(dir, "object.bin")
+	progress := utils.NewProgress(utils.ProgressNone)
+
+	err := downloadToFile(srv.URL, localPath, "server:/object.bin", TransferOptions{Progress: progress})
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(localPath)
+	assert.NoError(t, err)
+	assert.Equal(t, data, content)
+	assert.Equal(t, []string{"", "bytes=174762-"}, ranges)
+
+	_, err = os.Stat(filepath.Join(dir, partialStateFile))
+	assert.True(t, os.IsNotExist(err), "partial state should be removed after a successful download")
+}
+
+func TestDownloadToFileResumeFromState(t *testing.T) {
+	data := []byte("0123456789abcdefghij")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "bytes=10-", r.Header.Get("Range"))
+		w.Header().Set("ETag", `"etag"`)
+		http.ServeContent(w, r, "object", time.Time{}, bytes.NewReader(data))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	localPath := filepath.Join(dir, "object.bin")
+	assert.NoError(t, os.WriteFile(localPath+partialSuffix, data[:10], 0644))
+	assert.NoError(t, savePartialState(dir, PartialState{
+		Downloads: map[string]PartialDownload{
+			"object.bin": {Source: "server:/object.bin", ETag: `"etag"`, Size: int64(len(data))},
+		},
+	}))
+
+	opts := TransferOptions{Resume: true, Progress: utils.NewProgress(utils.ProgressNone)}
+	err := downloadToFile(srv.URL, localPath, "server:/object.bin", opts)
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(localPath)
+	assert.NoError(t, err)
+	assert.Equal(t, data, content)
+}
+
+func TestRemoteSourceReopensForRetries(t *testing.T) {
+	data := []byte("SELECT 1;\n")
+	requests := 0
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write(data)
+	}))
+	defer srv.Close()
+
+	src, err := newRemoteSource(srv.URL, "dump.sql", true)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(data)), src.size)
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		body, err := src.open()
+		assert.NoError(t, err)
+		content, err := io.ReadAll(body)
+		assert.NoError(t, err)
+		assert.NoError(t, body.Close())
+		assert.Equal(t, data, content)
+		assert.Equal(t, attempt, requests)
+	}
+}
+
+func TestRemoteSourceUnwrapsWrappedFile(t *testing.T) {
+	data := bytes.Repeat([]byte("SELECT 1;\n"), 1000)
+
+	var archive bytes.Buffer
+	zw := zip.NewWriter(&archive)
+	fw, err := zw.Create("dump.sql")
+	assert.NoError(t, err)
+	_, err = fw.Write(data)
+	assert.NoError(t, err)
+	assert.NoError(t, zw.Close())
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "object", time.Time{}, bytes.NewReader(archive.Bytes()))
+	}))
+	defer srv.Close()
+
+	src, err := newRemoteSource(srv.URL, "dump.sql", true)
+	assert.NoError(t, err)
+	defer src.release()
+	assert.Equal(t, int64(len(data)), src.size)
+
+	body, err := src.open()
+	assert.NoError(t, err)
+	content, err := io.ReadAll(body)
+	assert.NoError(t, err)
+	assert.NoError(t, body.Close())
+	assert.Equal(t, data, content)
+}
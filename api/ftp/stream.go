@@ -0,0 +1,220 @@
+package ftp
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/alpacanetworks/alpacon-cli/api/server"
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"github.com/google/uuid"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// UploadStream uploads everything read from r to the file named by dest ([SERVER_NAME]:/remote/path/file).
+// Nothing is written to local disk. A regular file, such as redirected standard input, is read in place.
+// Other input has an unknown size: small input is buffered in memory, larger input is streamed as a multipart upload.
+// As that needs a server supporting multipart uploads, such input is refused before reading it when the server does not.
+func UploadStream(ac *client.AlpaconClient, r io.Reader, dest string, opts TransferOptions) (string, error) {
+	serverName, remotePath := utils.SplitPath(dest)
+	if remotePath == "" || strings.HasSuffix(remotePath, "/") {
+		return "", fmt.Errorf("the destination %s must name a file", dest)
+	}
+
+	serverID, err := server.GetServerIDByName(ac, serverName)
+	if err != nil {
+		return "", err
+	}
+
+	var source uploadSource
+	var checksum func() (string, error)
+	if file, ok := regularFile(r); ok {
+		source, checksum, err = newSeekableSource(file, path.Base(remotePath))
+	} else if supportsMultipart(ac) {
+		hash := sha256.New()
+		source, err = newStreamSource(io.TeeReader(r, hash), path.Base(remotePath))
+		checksum = func() (string, error) {
+			return hex.EncodeToString(hash.Sum(nil)), nil
+		}
+	} else {
+		return "", fmt.Errorf("the server does not accept multipart uploads, so input of unknown size cannot be streamed; redirect it from a file instead")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	uploadRequest := &UploadRequest{
+		Id:             uuid.New().String(),
+		Name:           path.Base(remotePath),
+		Path:           path.Dir(remotePath),
+		Server:         serverID,
+		Username:       opts.Username,
+		Groupname:      opts.Groupname,
+		AllowOverwrite: "true",
+	}
+
+	result, err := uploadObject(ac, uploadRequest, source, opts)
+	if err != nil || !opts.Verify {
+		return result, err
+	}
+	sum, err := checksum()
+	if err != nil {
+		return result, err
+	}
+	return result, verifyStream(ac, serverName, remotePath, sum, opts)
+}
+
+// regularFile returns r as a file if it is a regular file, whose size is known and which can be read again.
+func regularFile(r io.Reader) (*os.File, bool) {
+	file, ok := r.(*os.File)
+	if !ok {
+		return nil, false
+	}
+	info, err := file.Stat()
+	if err != nil || !info.Mode().IsRegular() {
+		return nil, false
+	}
+	return file, true
+}
+
+// newSeekableSource reads file from its current offset to the end, seeking back for each retry.
+// The returned checksum hashes the same range.
+func newSeekableSource(file *os.File, name string) (uploadSource, func() (string, error), error) {
+	info, err := file.Stat()
+	if err != nil {
+		return uploadSource{}, nil, err
+	}
+	start, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return uploadSource{}, nil, err
+	}
+	size := info.Size() - start
+
+	read := func() (io.Reader, error) {
+		if _, err := file.Seek(start, io.SeekStart); err != nil {
+			return nil, err
+		}
+		return io.LimitReader(file, size), nil
+	}
+	source := uploadSource{
+		name:    name,
+		size:    size,
+		modTime: info.ModTime(),
+		open: func() (io.ReadCloser, error) {
+			r, err := read()
+			if err != nil {
+				return nil, err
+			}
+			return io.NopCloser(r), nil
+		},
+	}
+	checksum := func() (string, error) {
+		r, err := read()
+		if err != nil {
+			return "", err
+		}
+		hash := sha256.New()
+		if _, err = io.Copy(hash, r); err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(hash.Sum(nil)), nil
+	}
+	return source, checksum, nil
+}
+
+// DownloadStream writes the remote file src ([SERVER_NAME]:/remote/path/file) to w as it is received.
+// A file the server staged inside an archive is unwrapped, so w always receives the file itself.
+func DownloadStream(ac *client.AlpaconClient, src string, w io.Writer, opts TransferOptions) error {
+	serverName, remotePathStr := utils.SplitPath(src)
+	serverID, err := server.GetServerIDByName(ac, serverName)
+	if err != nil {
+		return err
+	}
+
+	remotePaths, err := expandRemotePaths(ac, serverName, remotePathStr, opts)
+	if err != nil {
+		return err
+	}
+	if len(remotePaths) != 1 {
+		return fmt.Errorf("exactly one remote file can be written to standard output, but %s names %d", src, len(remotePaths))
+	}
+	remotePath := remotePaths[0]
+
+	downloadResponse, err := stageDownload(ac, serverID, remotePath, "file", opts)
+	if err != nil {
+		return err
+	}
+
+	name := path.Base(remotePath)
+	wrapped, err := stagedWrappedFile(downloadResponse.DownloadURL, name)
+	if err != nil {
+		return err
+	}
+
+	hash := sha256.New()
+	if wrapped != nil {
+		err = wrapped.writeTo(io.MultiWriter(w, hash), name, opts.Progress)
+	} else {
+		err = downloadToWriter(downloadResponse.DownloadURL, io.MultiWriter(w, hash), name, opts.Progress)
+	}
+	if err != nil || !opts.Verify {
+		return err
+	}
+	return verifyStream(ac, serverName, remotePath, hex.EncodeToString(hash.Sum(nil)), opts)
+}
+
+// newStreamSource reads up to multipartThreshold bytes of r ahead. Input that fits is kept in memory,
+// so its size is known and retries can replay it. Larger input has an unknown size and can only be read once,
+// which requires a multipart upload.
+func newStreamSource(r io.Reader, name string) (uploadSource, error) {
+	head, err := io.ReadAll(io.LimitReader(r, multipartThreshold+1))
+	if err != nil {
+		return uploadSource{}, err
+	}
+
+	if len(head) <= multipartThreshold {
+		return uploadSource{
+			name:    name,
+			size:    int64(len(head)),
+			modTime: time.Now(),
+			open: func() (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(head)), nil
+			},
+		}, nil
+	}
+
+	opened := false
+	return uploadSource{
+		name:    name,
+		size:    -1,
+		modTime: time.Now(),
+		open: func() (io.ReadCloser, error) {
+			if opened {
+				return nil, permanentError{fmt.Errorf("the input of %s cannot be read again", name)}
+			}
+			opened = true
+			return io.NopCloser(io.MultiReader(bytes.NewReader(head), r)), nil
+		},
+	}, nil
+}
+
+// verifyStream compares sum, the SHA-256 of the streamed data, with remotePath on serverName.
+func verifyStream(ac *client.AlpaconClient, serverName, remotePath, sum string, opts TransferOptions) error {
+	remoteSums, err := remoteChecksums(ac, serverName, remotePath, false, opts)
+	if err != nil {
+		return err
+	}
+
+	streamSums := map[string]string{path.Base(remotePath): sum}
+	if err = compareChecksums(streamSums, remoteSums, "stream", serverName); err != nil {
+		return fmt.Errorf("checksum verification failed for %s:\n  %v", serverName+":"+remotePath, err)
+	}
+
+	utils.CliInfo("SHA-256 verified for %s.", serverName+":"+remotePath)
+	return nil
+}
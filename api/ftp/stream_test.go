@@ -0,0 +1,175 @@
+package ftp
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUploadPartsStreamsUnknownSize(t *testing.T) {
+	stored := make(map[int][]byte)
+	var completed CompleteUploadRequest
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			_ = json.NewDecoder(r.Body).Decode(&completed)
+			return
+		}
+		var partNumber int
+		_, _ = fmt.Sscanf(r.URL.Path, "/part/%d", &partNumber)
+		stored[partNumber], _ = io.ReadAll(r.Body)
+		w.Header().Set("ETag", fmt.Sprintf(`"%d"`, partNumber))
+	}))
+	defer srv.Close()
+
+	entry := &PartialUpload{Response: UploadResponse{Id: "id", PartSize: 4}}
+	for i := 1; i <= 4; i++ {
+		entry.Response.Parts = append(entry.Response.Parts, UploadPart{PartNumber: i, UploadUrl: fmt.Sprintf("%s/part/%d", srv.URL, i)})
+	}
+
+	ac := &client.AlpaconClient{HTTPClient: srv.Client(), BaseURL: srv.URL}
+	src, err := newStreamSource(strings.NewReader("0123456789"), "stdin")
+	assert.NoError(t, err)
+	src.size = -1
+
+	err = uploadParts(ac, src, entry, "", "", utils.NewProgress(utils.ProgressNone))
+	assert.NoError(t, err)
+	assert.Equal(t, map[int][]byte{1: []byte("0123"), 2: []byte("4567"), 3: []byte("89")}, stored)
+	assert.Len(t, completed.Parts, 3)
+}
+
+func TestUploadPartsRejectsOversizedStream(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	entry := &PartialUpload{Response: UploadResponse{Id: "id", PartSize: 4, Parts: []UploadPart{{PartNumber: 1, UploadUrl: srv.URL}}}}
+	src := uploadSource{name: "stdin", size: -1, open: func() (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader("0123456789")), nil
+	}}
+
+	ac := &client.AlpaconClient{HTTPClient: srv.Client(), BaseURL: srv.URL}
+	err := uploadParts(ac, src, entry, "", "", utils.NewProgress(utils.ProgressNone))
+	assert.Error(t, err)
+}
+
+func TestDownloadToWriterResumesDroppedConnection(t *testing.T) {
+	data := bytes.Repeat([]byte("alpacon!"), 64*1024)
+	requests := 0
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"etag"`)
+		requests++
+		if requests == 1 {
+			w.Header().Set("Content-Length", "524288")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(data[:len(data)/3])
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			_ = conn.Close()
+			return
+		}
+		assert.Equal(t, "bytes=174762-", r.Header.Get("Range"))
+		http.ServeContent(w, r, "object", time.Time{}, bytes.NewReader(data))
+	}))
+	defer srv.Close()
+
+	var out bytes.Buffer
+	err := downloadToWriter(srv.URL, &out, "object.bin", utils.NewProgress(utils.ProgressNone))
+	assert.NoError(t, err)
+	assert.Equal(t, data, out.Bytes())
+}
+
+func TestStagedWrappedFileWritesContents(t *testing.T) {
+	data := bytes.Repeat([]byte("alpacon!"), 4096)
+
+	var archive bytes.Buffer
+	zw := zip.NewWriter(&archive)
+	fw, err := zw.Create("object.bin")
+	assert.NoError(t, err)
+	_, err = fw.Write(data)
+	assert.NoError(t, err)
+	assert.NoError(t, zw.Close())
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "object", time.Time{}, bytes.NewReader(archive.Bytes()))
+	}))
+	defer srv.Close()
+
+	wrapped, err := stagedWrappedFile(srv.URL, "other.bin")
+	assert.NoError(t, err)
+	assert.Nil(t, wrapped, "an archive holding another file must be kept as is")
+
+	wrapped, err = stagedWrappedFile(srv.URL, "object.bin")
+	assert.NoError(t, err)
+	if assert.NotNil(t, wrapped) {
+		var out bytes.Buffer
+		assert.NoError(t, wrapped.writeTo(&out, "object.bin", utils.NewProgress(utils.ProgressNone)))
+		assert.Equal(t, data, out.Bytes())
+	}
+}
+
+type recordingReader struct {
+	read bool
+}
+
+func (r *recordingReader) Read(p []byte) (int, error) {
+	r.read = true
+	return 0, io.EOF
+}
+
+func TestUploadStreamRefusesPipeWithoutMultipart(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodOptions {
+			_, _ = w.Write([]byte(`{"actions": {"POST": {"name": {}}}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"count": 1, "results": [{"id": "s1", "name": "db"}]}`))
+	}))
+	defer srv.Close()
+
+	ac := &client.AlpaconClient{HTTPClient: srv.Client(), BaseURL: srv.URL}
+	input := &recordingReader{}
+	_, err := UploadStream(ac, input, "db:/backups/today.sql", TransferOptions{Progress: utils.NewProgress(utils.ProgressNone)})
+	assert.Error(t, err)
+	assert.False(t, input.read, "no input should be consumed before failing")
+}
+
+func TestSeekableSourceRereadsFromStart(t *testing.T) {
+	file, err := os.CreateTemp(t.TempDir(), "stdin")
+	assert.NoError(t, err)
+	defer func() { _ = file.Close() }()
+	_, err = file.WriteString("skip:SELECT 1;\n")
+	assert.NoError(t, err)
+	_, err = file.Seek(int64(len("skip:")), io.SeekStart)
+	assert.NoError(t, err)
+
+	src, checksum, err := newSeekableSource(file, "dump.sql")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len("SELECT 1;\n")), src.size)
+
+	for attempt := 0; attempt < 2; attempt++ {
+		body, err := src.open()
+		assert.NoError(t, err)
+		content, err := io.ReadAll(body)
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT 1;\n", string(content))
+	}
+
+	sum, err := checksum()
+	assert.NoError(t, err)
+	expected := sha256.Sum256([]byte("SELECT 1;\n"))
+	assert.Equal(t, hex.EncodeToString(expected[:]), sum)
+}
@@ -0,0 +1,394 @@
+package ftp
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/alpacanetworks/alpacon-cli/api/event"
+	"github.com/alpacanetworks/alpacon-cli/api/server"
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"github.com/google/uuid"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	syncActionUpload   = "upload"
+	syncActionDownload = "download"
+	syncActionDelete   = "delete"
+
+	// remoteBatchSize bounds the number of paths passed to a single remote command.
+	remoteBatchSize = 100
+)
+
+// SyncToRemote makes the remote directory dest (SERVER:/path) mirror the local directory src.
+func SyncToRemote(ac *client.AlpaconClient, src, dest string, opts SyncOptions) ([]SyncAttributes, error) {
+	serverName, remoteRoot := utils.SplitPath(dest)
+
+	localFiles, err := localManifest(src, false, opts)
+	if err != nil {
+		return nil, err
+	}
+	remoteFiles, err := remoteManifest(ac, serverName, remoteRoot, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	actions := planSync(localFiles, remoteFiles, syncActionUpload, opts)
+	if opts.DryRun || len(actions) == 0 {
+		return syncAttributes(actions), nil
+	}
+
+	serverID, err := server.GetServerIDByName(ac, serverName)
+	if err != nil {
+		return nil, err
+	}
+
+	var uploads, deletes []string
+	for _, action := range actions {
+		if action.Action == syncActionDelete {
+			deletes = append(deletes, action.Path)
+		} else {
+			uploads = append(uploads, action.Path)
+		}
+	}
+
+	if err = runRemoteBatches(ac, serverName, remoteRoot, "mkdir -p --", remoteDirs(uploads), opts.TransferOptions); err != nil {
+		return nil, err
+	}
+
+	_, err = runParallel(len(uploads), opts.Parallel, func(i int) (string, error) {
+		relPath := uploads[i]
+		source, err := newFileSource(filepath.Join(src, filepath.FromSlash(relPath)))
+		if err != nil {
+			return "", err
+		}
+
+		uploadRequest := &UploadRequest{
+			Id:             uuid.New().String(),
+			Name:           source.name,
+			Path:           path.Join(remoteRoot, path.Dir(relPath)),
+			Server:         serverID,
+			Username:       opts.Username,
+			Groupname:      opts.Groupname,
+			AllowOverwrite: "true",
+		}
+		return uploadObject(ac, uploadRequest, source, opts.TransferOptions)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Carry local modification times over so that the next run sees the files as unchanged.
+	var touches []string
+	for _, relPath := range uploads {
+		touches = append(touches, fmt.Sprintf("touch -m -d @%d -- %s", localFiles[relPath].ModTime.Unix(), utils.ShellQuote(relPath)))
+	}
+	if err = runRemoteCommands(ac, serverName, remoteRoot, touches, opts.TransferOptions); err != nil {
+		return nil, err
+	}
+
+	if err = runRemoteBatches(ac, serverName, remoteRoot, "rm -f --", deletes, opts.TransferOptions); err != nil {
+		return nil, err
+	}
+
+	return syncAttributes(actions), nil
+}
+
+// SyncFromRemote makes the local directory dest mirror the remote directory src (SERVER:/path).
+func SyncFromRemote(ac *client.AlpaconClient, src, dest string, opts SyncOptions) ([]SyncAttributes, error) {
+	serverName, remoteRoot := utils.SplitPath(src)
+
+	remoteFiles, err := remoteManifest(ac, serverName, remoteRoot, opts)
+	if err != nil {
+		return nil, err
+	}
+	localFiles, err := localManifest(dest, true, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	actions := planSync(remoteFiles, localFiles, syncActionDownload, opts)
+	if opts.DryRun || len(actions) == 0 {
+		return syncAttributes(actions), nil
+	}
+
+	serverID, err := server.GetServerIDByName(ac, serverName)
+	if err != nil {
+		return nil, err
+	}
+
+	transferOpts := opts.TransferOptions
+	transferOpts.Recursive = false
+	_, err = runParallel(len(actions), opts.Parallel, func(i int) (string, error) {
+		action := actions[i]
+		localPath := filepath.Join(dest, filepath.FromSlash(action.Path))
+
+		if action.Action == syncActionDelete {
+			return "", os.Remove(localPath)
+		}
+
+		err := downloadObject(ac, serverID, serverName, path.Join(remoteRoot, action.Path), "file", filepath.Dir(localPath), transferOpts)
+		if err != nil {
+			return "", err
+		}
+		modTime := remoteFiles[action.Path].ModTime
+		return "", os.Chtimes(localPath, modTime, modTime)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return syncAttributes(actions), nil
+}
+
+// planSync lists the actions that make dst identical to src, ordered by path.
+// transfer is the action used for new or changed files.
+func planSync(src, dst map[string]SyncFile, transfer string, opts SyncOptions) []SyncAction {
+	var actions []SyncAction
+
+	for relPath, srcFile := range src {
+		dstFile, ok := dst[relPath]
+		reason := ""
+		switch {
+		case !ok:
+			reason = "new"
+		case srcFile.Size != dstFile.Size:
+			reason = "size"
+		case opts.Checksum && srcFile.Hash != dstFile.Hash:
+			reason = "checksum"
+		case !opts.Checksum && srcFile.ModTime.Unix() != dstFile.ModTime.Unix():
+			reason = "mtime"
+		}
+		if reason != "" {
+			actions = append(actions, SyncAction{Action: transfer, Path: relPath, Size: srcFile.Size, Reason: reason})
+		}
+	}
+
+	if opts.Delete {
+		for relPath, dstFile := range dst {
+			if _, ok := src[relPath]; !ok {
+				actions = append(actions, SyncAction{Action: syncActionDelete, Path: relPath, Size: dstFile.Size, Reason: "extraneous"})
+			}
+		}
+	}
+
+	sort.Slice(actions, func(i, j int) bool {
+		return actions[i].Path < actions[j].Path
+	})
+	return actions
+}
+
+// isExcluded reports whether relPath, or any directory containing it, matches one of the exclude patterns.
+// Patterns use filepath.Match syntax and are tested against both the full relative path and each name.
+func isExcluded(relPath string, patterns []string) bool {
+	parts := strings.Split(relPath, "/")
+	for i := range parts {
+		prefix := strings.Join(parts[:i+1], "/")
+		for _, pattern := range patterns {
+			pattern = strings.TrimSuffix(pattern, "/")
+			if ok, _ := path.Match(pattern, prefix); ok {
+				return true
+			}
+			if ok, _ := path.Match(pattern, parts[i]); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// localManifest lists regular files below root keyed by slash-separated relative path.
+// With missingOK, a missing root yields an empty manifest so that the first sync into a new directory works.
+// Sources must exist, as an empty source manifest would make --delete remove everything at the destination.
+func localManifest(root string, missingOK bool, opts SyncOptions) (map[string]SyncFile, error) {
+	files := make(map[string]SyncFile)
+
+	info, err := os.Stat(root)
+	if err != nil {
+		if os.IsNotExist(err) && missingOK {
+			return files, nil
+		}
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", root)
+	}
+
+	err = filepath.Walk(root, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if filePath == root {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(root, filePath)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		if isExcluded(relPath, opts.Exclude) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() || strings.HasSuffix(relPath, partialSuffix) || info.Name() == partialStateFile {
+			return nil
+		}
+
+		file := SyncFile{Size: info.Size(), ModTime: info.ModTime()}
+		if opts.Checksum {
+			if file.Hash, err = fileSHA256(filePath); err != nil {
+				return err
+			}
+		}
+		files[relPath] = file
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// remoteManifest lists regular files below root on serverName, gathered with find (and sha256sum with opts.Checksum).
+func remoteManifest(ac *client.AlpaconClient, serverName, root string, opts SyncOptions) (map[string]SyncFile, error) {
+	command := fmt.Sprintf("cd %s 2>/dev/null || exit 0; find . -type f -printf '%%s %%T@ %%P\\n'", utils.ShellQuote(root))
+	output, err := event.RunCommand(ac, serverName, command, opts.Username, opts.Groupname, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := parseManifest(output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s:%s: %v", serverName, root, err)
+	}
+
+	for relPath := range files {
+		if isExcluded(relPath, opts.Exclude) {
+			delete(files, relPath)
+		}
+	}
+
+	if opts.Checksum && len(files) > 0 {
+		sums, err := remoteChecksums(ac, serverName, root, true, opts.TransferOptions)
+		if err != nil {
+			return nil, err
+		}
+		for relPath, file := range files {
+			file.Hash = sums[relPath]
+			files[relPath] = file
+		}
+	}
+	return files, nil
+}
+
+// parseManifest parses lines of "<size> <unix mtime> <relative path>" as printed by find -printf.
+func parseManifest(output string) (map[string]SyncFile, error) {
+	files := make(map[string]SyncFile)
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+
+		fields := strings.SplitN(line, " ", 3)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("unexpected output: %q", line)
+		}
+		size, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected output: %q", line)
+		}
+		mtime, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected output: %q", line)
+		}
+
+		files[fields[2]] = SyncFile{
+			Size:    size,
+			ModTime: time.Unix(int64(mtime), 0),
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// remoteDirs returns the distinct parent directories of relPaths, excluding the root itself.
+func remoteDirs(relPaths []string) []string {
+	seen := make(map[string]bool)
+	var dirs []string
+	for _, relPath := range relPaths {
+		dir := path.Dir(relPath)
+		if dir != "." && !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+// runRemoteBatches runs "command path..." inside root, passing at most remoteBatchSize paths per invocation.
+func runRemoteBatches(ac *client.AlpaconClient, serverName, root, command string, relPaths []string, opts TransferOptions) error {
+	var commands []string
+	for start := 0; start < len(relPaths); start += remoteBatchSize {
+		end := start + remoteBatchSize
+		if end > len(relPaths) {
+			end = len(relPaths)
+		}
+
+		quoted := make([]string, 0, end-start)
+		for _, relPath := range relPaths[start:end] {
+			quoted = append(quoted, utils.ShellQuote(relPath))
+		}
+		commands = append(commands, command+" "+strings.Join(quoted, " "))
+	}
+	return runRemoteCommands(ac, serverName, root, commands, opts)
+}
+
+// runRemoteCommands runs commands inside root, chaining up to remoteBatchSize of them per remote command.
+func runRemoteCommands(ac *client.AlpaconClient, serverName, root string, commands []string, opts TransferOptions) error {
+	for start := 0; start < len(commands); start += remoteBatchSize {
+		end := start + remoteBatchSize
+		if end > len(commands) {
+			end = len(commands)
+		}
+
+		line := fmt.Sprintf("mkdir -p -- %s && cd %s && %s && echo ok",
+			utils.ShellQuote(root), utils.ShellQuote(root), strings.Join(commands[start:end], " && "))
+		output, err := event.RunCommand(ac, serverName, line, opts.Username, opts.Groupname, nil)
+		if err != nil {
+			return err
+		}
+		if !strings.HasSuffix(strings.TrimSpace(output), "ok") {
+			return fmt.Errorf("remote command failed on %s: %s", serverName, strings.TrimSpace(output))
+		}
+	}
+	return nil
+}
+
+func syncAttributes(actions []SyncAction) []SyncAttributes {
+	var attributes []SyncAttributes
+	for _, action := range actions {
+		attributes = append(attributes, SyncAttributes{
+			Action: action.Action,
+			Path:   action.Path,
+			Size:   utils.FormatBytes(action.Size),
+			Reason: action.Reason,
+		})
+	}
+	return attributes
+}
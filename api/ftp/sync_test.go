@@ -0,0 +1,93 @@
+package ftp
+
+import (
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPlanSync(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	src := map[string]SyncFile{
+		"index.html":    {Size: 10, ModTime: now},
+		"app.js":        {Size: 20, ModTime: now},
+		"css/style.css": {Size: 30, ModTime: now},
+		"new.txt":       {Size: 5, ModTime: now},
+	}
+	dst := map[string]SyncFile{
+		"index.html":    {Size: 10, ModTime: now},
+		"app.js":        {Size: 21, ModTime: now},
+		"css/style.css": {Size: 30, ModTime: now.Add(-time.Hour)},
+		"old.txt":       {Size: 7, ModTime: now},
+	}
+
+	actions := planSync(src, dst, syncActionUpload, SyncOptions{})
+	assert.Equal(t, []SyncAction{
+		{Action: syncActionUpload, Path: "app.js", Size: 20, Reason: "size"},
+		{Action: syncActionUpload, Path: "css/style.css", Size: 30, Reason: "mtime"},
+		{Action: syncActionUpload, Path: "new.txt", Size: 5, Reason: "new"},
+	}, actions)
+
+	actions = planSync(src, dst, syncActionUpload, SyncOptions{Delete: true})
+	assert.Contains(t, actions, SyncAction{Action: syncActionDelete, Path: "old.txt", Size: 7, Reason: "extraneous"})
+}
+
+func TestPlanSyncChecksumIgnoresModTime(t *testing.T) {
+	src := map[string]SyncFile{"a.txt": {Size: 1, ModTime: time.Unix(1, 0), Hash: "aa"}}
+	dst := map[string]SyncFile{"a.txt": {Size: 1, ModTime: time.Unix(2, 0), Hash: "aa"}}
+
+	assert.Empty(t, planSync(src, dst, syncActionDownload, SyncOptions{Checksum: true}))
+
+	dst["a.txt"] = SyncFile{Size: 1, ModTime: time.Unix(1, 0), Hash: "bb"}
+	assert.Equal(t, "checksum", planSync(src, dst, syncActionDownload, SyncOptions{Checksum: true})[0].Reason)
+}
+
+func TestIsExcluded(t *testing.T) {
+	patterns := []string{"*.map", "node_modules", "build/tmp/"}
+
+	assert.True(t, isExcluded("js/app.js.map", patterns))
+	assert.True(t, isExcluded("node_modules/lib/index.js", patterns))
+	assert.True(t, isExcluded("build/tmp/cache.bin", patterns))
+	assert.False(t, isExcluded("build/app.js", patterns))
+	assert.False(t, isExcluded("src/node_modules.txt", patterns))
+}
+
+func TestParseManifest(t *testing.T) {
+	files, err := parseManifest("12 1700000000.5000000000 index.html\n0 1700000001.0000000000 sub dir/empty file\n")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]SyncFile{
+		"index.html":         {Size: 12, ModTime: time.Unix(1700000000, 0)},
+		"sub dir/empty file": {Size: 0, ModTime: time.Unix(1700000001, 0)},
+	}, files)
+
+	_, err = parseManifest("find: '/srv/app': Permission denied\n")
+	assert.Error(t, err)
+}
+
+func TestSyncToRemoteRejectsMissingSource(t *testing.T) {
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	ac := &client.AlpaconClient{HTTPClient: srv.Client(), BaseURL: srv.URL}
+	missing := filepath.Join(t.TempDir(), "unmounted")
+	opts := SyncOptions{Delete: true, TransferOptions: TransferOptions{Progress: utils.NewProgress(utils.ProgressNone)}}
+
+	_, err := SyncToRemote(ac, missing, "web-1:/srv/app", opts)
+	assert.Error(t, err)
+	assert.Empty(t, requests, "nothing should be listed or deleted on the server")
+}
+
+func TestLocalManifestAllowsMissingDestination(t *testing.T) {
+	files, err := localManifest(filepath.Join(t.TempDir(), "new"), true, SyncOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, files)
+}
@@ -1,6 +1,9 @@
 package ftp
 
-import "time"
+import (
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"time"
+)
 
 type DownloadRequest struct {
 	Path         string `json:"path"`
@@ -35,6 +38,8 @@ type UploadRequest struct {
 	Groupname      string `json:"groupname"`
 	AllowUnzip     string `json:"allow_unzip"`
 	AllowOverwrite string `json:"allow_overwrite"`
+	// Multipart is only sent to servers that advertise it, see supportsMultipart.
+	Multipart string `json:"multipart,omitempty"`
 }
 
 type UploadResponse struct {
@@ -49,4 +54,86 @@ type UploadResponse struct {
 	ExpiresAt time.Time `json:"expires_at"`
 	UploadUrl string    `json:"upload_url"`
 	Command   string    `json:"command"`
+	// PartSize and Parts are only set when a multipart upload was requested and the storage backend accepts it.
+	// Otherwise the object is sent to UploadUrl with a single PUT.
+	PartSize int64        `json:"part_size,omitempty"`
+	Parts    []UploadPart `json:"parts,omitempty"`
+}
+
+type UploadPart struct {
+	PartNumber int    `json:"part_number"`
+	UploadUrl  string `json:"upload_url"`
+}
+
+type CompletedPart struct {
+	PartNumber int    `json:"part_number"`
+	ETag       string `json:"etag"`
+}
+
+type CompleteUploadRequest struct {
+	Parts []CompletedPart `json:"parts"`
+}
+
+// PartialState is persisted in a partialStateFile so interrupted transfers can be resumed.
+// Uploads are recorded under the config directory, downloads in the directory they are written to.
+type PartialState struct {
+	Uploads   map[string]PartialUpload   `json:"uploads,omitempty"`
+	Downloads map[string]PartialDownload `json:"downloads,omitempty"`
+}
+
+type PartialUpload struct {
+	Dest      string          `json:"dest"`
+	Size      int64           `json:"size"`
+	ModTime   time.Time       `json:"mod_time"`
+	Response  UploadResponse  `json:"response"`
+	Completed []CompletedPart `json:"completed,omitempty"`
+	Uploaded  bool            `json:"uploaded"`
+}
+
+type PartialDownload struct {
+	Source string    `json:"source"`
+	ETag   string    `json:"etag"`
+	Size   int64     `json:"size"`
+	Added  time.Time `json:"added"`
+}
+
+// TransferOptions controls how files are copied between the local machine and a server.
+type TransferOptions struct {
+	Username  string
+	Groupname string
+	Recursive bool
+	Resume    bool
+	Parallel  int
+	Verify    bool
+	Progress  *utils.Progress
+}
+
+// SyncOptions controls how a directory tree is synchronized between the local machine and a server.
+type SyncOptions struct {
+	TransferOptions
+	Delete   bool
+	DryRun   bool
+	Checksum bool
+	Exclude  []string
+}
+
+// SyncFile is a manifest entry of a file taking part in a sync.
+type SyncFile struct {
+	Size    int64
+	ModTime time.Time
+	Hash    string
+}
+
+type SyncAction struct {
+	Action string
+	Path   string
+	Size   int64
+	Reason string
+}
+
+type SyncAttributes struct {
+	Action string `json:"action"`
+	Path   string `json:"path"`
+	Size   string `json:"size"`
+	Reason string `json:"reason"`
 }
@@ -0,0 +1,207 @@
+package ftp
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/alpacanetworks/alpacon-cli/api/event"
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// verifyTransfer compares SHA-256 checksums of localPath with remotePath on serverName.
+// Both may be a single file or a directory, in which case every regular file below it is compared.
+func verifyTransfer(ac *client.AlpaconClient, serverName, remotePath, localPath string, opts TransferOptions) error {
+	localSums, isDir, err := localChecksums(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to compute local checksum of %s: %v", localPath, err)
+	}
+
+	remoteSums, err := remoteChecksums(ac, serverName, remotePath, isDir, opts)
+	if err != nil {
+		return err
+	}
+
+	if err = compareChecksums(localSums, remoteSums, "local", serverName); err != nil {
+		return fmt.Errorf("checksum verification failed for %s:\n  %v", localPath, err)
+	}
+
+	utils.CliInfo("SHA-256 verified for %s (%d file(s)).", localPath, len(localSums))
+	return nil
+}
+
+// verifyRemoteCopy compares SHA-256 checksums of srcPath on srcServer with destPath on destServer.
+func verifyRemoteCopy(ac *client.AlpaconClient, srcServer, srcPath, destServer, destPath string, isDir bool, opts TransferOptions) error {
+	srcSums, err := remoteChecksums(ac, srcServer, srcPath, isDir, opts)
+	if err != nil {
+		return err
+	}
+	destSums, err := remoteChecksums(ac, destServer, destPath, isDir, opts)
+	if err != nil {
+		return err
+	}
+	if err = compareChecksums(srcSums, destSums, srcServer, destServer); err != nil {
+		return fmt.Errorf("checksum verification failed for %s:\n  %v", destServer+":"+destPath, err)
+	}
+
+	utils.CliInfo("SHA-256 verified for %s (%d file(s)).", destServer+":"+destPath, len(srcSums))
+	return nil
+}
+
+// compareChecksums returns an error listing every file whose checksum differs between the sides a and b,
+// or that exists on only one of them. aName and bName label the sides in the message.
+func compareChecksums(a, b map[string]string, aName, bName string) error {
+	var mismatches []string
+	for name, aSum := range a {
+		bSum, ok := b[name]
+		switch {
+		case !ok:
+			mismatches = append(mismatches, fmt.Sprintf("%s: missing on %s", name, bName))
+		case bSum != aSum:
+			mismatches = append(mismatches, fmt.Sprintf("%s: %s %s, %s %s", name, aName, aSum, bName, bSum))
+		}
+	}
+	for name := range b {
+		if _, ok := a[name]; !ok {
+			mismatches = append(mismatches, fmt.Sprintf("%s: missing on %s", name, aName))
+		}
+	}
+
+	if len(mismatches) > 0 {
+		sort.Strings(mismatches)
+		return errors.New(strings.Join(mismatches, "\n  "))
+	}
+	return nil
+}
+
+// localChecksums returns the SHA-256 of localPath keyed by its base name, or of every file below it keyed
+// by slash-separated relative path when localPath is a directory.
+func localChecksums(localPath string) (map[string]string, bool, error) {
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return nil, false, err
+	}
+
+	sums := make(map[string]string)
+	if !info.IsDir() {
+		sum, err := fileSHA256(localPath)
+		if err != nil {
+			return nil, false, err
+		}
+		sums[filepath.Base(localPath)] = sum
+		return sums, false, nil
+	}
+
+	err = filepath.Walk(localPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(localPath, path)
+		if err != nil {
+			return err
+		}
+		sum, err := fileSHA256(path)
+		if err != nil {
+			return err
+		}
+		sums[filepath.ToSlash(relPath)] = sum
+		return nil
+	})
+	if err != nil {
+		return nil, true, err
+	}
+	return sums, true, nil
+}
+
+func fileSHA256(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = file.Close() }()
+
+	hash := sha256.New()
+	if _, err = io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// RemoteChecksum returns the SHA-256 of the file remotePath on serverName.
+func RemoteChecksum(ac *client.AlpaconClient, serverName, remotePath, username, groupname string) (string, error) {
+	sums, err := remoteChecksums(ac, serverName, remotePath, false, TransferOptions{Username: username, Groupname: groupname})
+	if err != nil {
+		return "", err
+	}
+	return sums[filepath.Base(remotePath)], nil
+}
+
+// remoteChecksums runs sha256sum on the server and returns the checksums keyed like localChecksums.
+func remoteChecksums(ac *client.AlpaconClient, serverName, remotePath string, isDir bool, opts TransferOptions) (map[string]string, error) {
+	var command string
+	if isDir {
+		command = fmt.Sprintf("cd %s && find . -type f -exec sha256sum -- {} +", utils.ShellQuote(remotePath))
+	} else {
+		command = fmt.Sprintf("sha256sum -- %s", utils.ShellQuote(remotePath))
+	}
+
+	output, err := event.RunCommand(ac, serverName, command, opts.Username, opts.Groupname, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute remote checksum of %s: %v", remotePath, err)
+	}
+
+	sums, err := parseChecksums(output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute remote checksum of %s: %s", remotePath, strings.TrimSpace(output))
+	}
+
+	if !isDir {
+		if len(sums) != 1 {
+			return nil, fmt.Errorf("failed to compute remote checksum of %s: %s", remotePath, strings.TrimSpace(output))
+		}
+		for _, sum := range sums {
+			return map[string]string{filepath.Base(remotePath): sum}, nil
+		}
+	}
+	return sums, nil
+}
+
+// parseChecksums parses sha256sum output ("<hex>  <name>" per line) into a map of name to checksum.
+func parseChecksums(output string) (map[string]string, error) {
+	sums := make(map[string]string)
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+
+		sum, name, ok := strings.Cut(line, " ")
+		if !ok || len(sum) != sha256.Size*2 {
+			return nil, fmt.Errorf("unexpected sha256sum output: %q", line)
+		}
+		if _, err := hex.DecodeString(sum); err != nil {
+			return nil, fmt.Errorf("unexpected sha256sum output: %q", line)
+		}
+
+		// sha256sum separates the name with " " in text mode and " *" in binary mode.
+		name = strings.TrimPrefix(strings.TrimPrefix(name, " "), "*")
+		sums[strings.TrimPrefix(name, "./")] = sum
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return sums, nil
+}
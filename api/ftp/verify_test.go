@@ -0,0 +1,23 @@
+package ftp
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseChecksums(t *testing.T) {
+	output := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855  ./empty.txt\n" +
+		"2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824 *sub dir/hello.txt\n"
+
+	sums, err := parseChecksums(output)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"empty.txt":         "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		"sub dir/hello.txt": "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+	}, sums)
+}
+
+func TestParseChecksumsRejectsErrors(t *testing.T) {
+	_, err := parseChecksums("sha256sum: /srv/app.tar: No such file or directory\n")
+	assert.Error(t, err)
+}
@@ -0,0 +1,182 @@
+package ftp
+
+import (
+	"archive/zip"
+	"compress/flate"
+	"errors"
+	"fmt"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"hash"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"time"
+)
+
+// zipSignature starts the local file header of a zip archive.
+const zipSignature = "PK\x03\x04"
+
+// wrappedFile is the single file of an archive the server staged in place of that file.
+// Only the file data is requested when it is opened, so the archive is never stored locally.
+type wrappedFile struct {
+	downloadURL    string
+	offset         int64
+	compressedSize int64
+	size           int64
+	method         uint16
+	crc32          uint32
+	modTime        time.Time
+}
+
+// findWrappedFile reports the file name if the object of size bytes staged at downloadURL is an archive holding only that file.
+// It returns nil for any other object. Only the central directory of the archive is read, using range requests.
+func findWrappedFile(downloadURL string, size int64, name string) (*wrappedFile, error) {
+	r, err := zip.NewReader(rangeReaderAt{downloadURL: downloadURL}, size)
+	if err != nil {
+		if errors.Is(err, zip.ErrFormat) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(r.File) != 1 || r.File[0].Name != name || !r.File[0].Mode().IsRegular() {
+		return nil, nil
+	}
+
+	f := r.File[0]
+	if f.Method != zip.Store && f.Method != zip.Deflate {
+		return nil, fmt.Errorf("unsupported compression method %d in the archive of %s", f.Method, name)
+	}
+	offset, err := f.DataOffset()
+	if err != nil {
+		return nil, err
+	}
+
+	return &wrappedFile{
+		downloadURL:    downloadURL,
+		offset:         offset,
+		compressedSize: int64(f.CompressedSize64),
+		size:           int64(f.UncompressedSize64),
+		method:         f.Method,
+		crc32:          f.CRC32,
+		modTime:        f.Modified,
+	}, nil
+}
+
+// stagedWrappedFile waits for the object staged at downloadURL and reports the file name if it is an archive holding only that file.
+func stagedWrappedFile(downloadURL, name string) (*wrappedFile, error) {
+	resp, err := waitForRemoteObject(downloadURL)
+	if err != nil {
+		return nil, err
+	}
+	head := make([]byte, len(zipSignature))
+	_, err = io.ReadFull(resp.Body, head)
+	_ = resp.Body.Close()
+	if err != nil || string(head) != zipSignature || resp.ContentLength < 0 {
+		// Objects too short for the signature are never archives.
+		return nil, nil
+	}
+	return findWrappedFile(downloadURL, resp.ContentLength, name)
+}
+
+// writeTo writes the contents of the wrapped file to w. Retries skip what has been written already.
+func (w *wrappedFile) writeTo(dst io.Writer, name string, progress *utils.Progress) error {
+	transfer := progress.Start(name, w.size)
+	defer transfer.Finish()
+
+	var written int64
+	return retryTransfer(name, func() error {
+		r, err := w.open()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = r.Close() }()
+
+		if _, err = io.CopyN(io.Discard, r, written); err != nil {
+			return err
+		}
+		n, err := io.Copy(permanentWriter{dst}, transfer.Reader(r))
+		written += n
+		return err
+	})
+}
+
+// open streams the contents of the wrapped file, checking them against the CRC-32 of the archive.
+func (w *wrappedFile) open() (io.ReadCloser, error) {
+	var body io.ReadCloser = http.NoBody
+	if w.compressedSize > 0 {
+		var err error
+		if body, err = requestRange(w.downloadURL, w.offset, w.compressedSize); err != nil {
+			return nil, err
+		}
+	}
+
+	var r io.ReadCloser = body
+	if w.method == zip.Deflate {
+		r = flate.NewReader(body)
+	}
+	return &wrappedReader{r: r, body: body, file: w, hash: crc32.NewIEEE()}, nil
+}
+
+type wrappedReader struct {
+	r    io.ReadCloser
+	body io.Closer
+	file *wrappedFile
+	hash hash.Hash32
+	read int64
+}
+
+func (r *wrappedReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.hash.Write(p[:n])
+	r.read += int64(n)
+	if err == io.EOF && (r.read != r.file.size || r.hash.Sum32() != r.file.crc32) {
+		err = fmt.Errorf("the archived file is corrupt")
+	}
+	return n, err
+}
+
+func (r *wrappedReader) Close() error {
+	_ = r.r.Close()
+	return r.body.Close()
+}
+
+// rangeReaderAt reads the object staged at downloadURL with a range request per call.
+type rangeReaderAt struct {
+	downloadURL string
+}
+
+func (r rangeReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	body, err := requestRange(r.downloadURL, off, int64(len(p)))
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = body.Close() }()
+
+	n, err := io.ReadFull(body, p)
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// requestRange requests length bytes of the object at downloadURL, starting at offset.
+func requestRange(downloadURL string, offset, length int64) (io.ReadCloser, error) {
+	req, err := http.NewRequest(http.MethodGet, downloadURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, offset+length-1))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusPartialContent {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("range request failed with status %s", resp.Status)
+	}
+	return resp.Body, nil
+}
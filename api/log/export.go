@@ -0,0 +1,126 @@
+package log
+
+import (
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"strconv"
+	"time"
+)
+
+// ExportCheckpoint records how far log entries have been exported, so the next export starts after them.
+type ExportCheckpoint struct {
+	LastID int `json:"last_id"`
+}
+
+// Advance moves the checkpoint past entries.
+func (c *ExportCheckpoint) Advance(entries []LogEntry) {
+	for _, entry := range entries {
+		if entry.ID > c.LastID {
+			c.LastID = entry.ID
+		}
+	}
+}
+
+// LatestCheckpoint returns a checkpoint at the newest log entry matching the server-side parts of filter,
+// so that only entries added later are exported.
+func LatestCheckpoint(ac *client.AlpaconClient, filter LogFilter) (ExportCheckpoint, error) {
+	var checkpoint ExportCheckpoint
+	params, err := filterParams(ac, filter)
+	if err != nil {
+		return checkpoint, err
+	}
+
+	entries, _, err := fetchLogPage(ac, params, 1, 1)
+	if err != nil {
+		return checkpoint, err
+	}
+	checkpoint.Advance(entries)
+	return checkpoint, nil
+}
+
+// ListLogsAfter returns every log entry matching filter with an ID above the checkpoint, oldest first.
+func ListLogsAfter(ac *client.AlpaconClient, filter LogFilter, checkpoint ExportCheckpoint) ([]LogEntry, error) {
+	params, err := filterParams(ac, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []LogEntry
+	for page := 1; page != 0; {
+		results, next, err := fetchLogPage(ac, params, page, listPageSize)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, results...)
+		page = next
+
+		if len(results) == 0 || minID(results) <= checkpoint.LastID {
+			break
+		}
+		if oldest := results[len(results)-1]; !filter.Since.IsZero() && oldest.Date.Before(filter.Since) {
+			break
+		}
+	}
+
+	entries, _ = entriesAfter(entries, checkpoint.LastID)
+	return filter.filter(entries), nil
+}
+
+func (entry LogEntry) CSVHeader() []string {
+	return []string{"id", "date", "server", "program", "level", "name", "path", "lineno", "pid", "tid", "process", "thread", "msg"}
+}
+
+func (entry LogEntry) CSVRecord() []string {
+	return []string{
+		strconv.Itoa(entry.ID),
+		entry.Date.UTC().Format(time.RFC3339Nano),
+		entry.ServerName,
+		entry.Program,
+		LevelName(entry.Level),
+		entry.Name,
+		entry.Path,
+		strconv.Itoa(entry.LineNo),
+		strconv.Itoa(entry.PID),
+		strconv.Itoa(entry.TID),
+		entry.Process,
+		entry.Thread,
+		entry.Msg,
+	}
+}
+
+// Syslog returns entry as a syslog message from the daemon facility, with the source location in structured data.
+func (entry LogEntry) Syslog() utils.SyslogMessage {
+	return utils.SyslogMessage{
+		Facility:  utils.SyslogFacilityDaemon,
+		Severity:  syslogSeverity(entry.Level),
+		Timestamp: entry.Date,
+		Hostname:  entry.ServerName,
+		AppName:   entry.Program,
+		ProcID:    strconv.Itoa(entry.PID),
+		MsgID:     entry.Name,
+		Params: map[string]string{
+			"id":      strconv.Itoa(entry.ID),
+			"path":    entry.Path,
+			"lineno":  strconv.Itoa(entry.LineNo),
+			"tid":     strconv.Itoa(entry.TID),
+			"process": entry.Process,
+			"thread":  entry.Thread,
+		},
+		Message: entry.Msg,
+	}
+}
+
+func syslogSeverity(level int) int {
+	switch {
+	case level >= 50:
+		return utils.SyslogSeverityCritical
+	case level >= 40:
+		return utils.SyslogSeverityError
+	case level >= 30:
+		return utils.SyslogSeverityWarning
+	case level >= 20:
+		return utils.SyslogSeverityInfo
+	default:
+		return utils.SyslogSeverityDebug
+	}
+}
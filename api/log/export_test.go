@@ -0,0 +1,43 @@
+package log
+
+import (
+	"encoding/json"
+	"github.com/alpacanetworks/alpacon-cli/api"
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestListLogsAfterStopsAtLastPage(t *testing.T) {
+	var requested []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		requested = append(requested, r.URL.Query().Get("page"))
+		if page > 2 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		response := api.ListResponse[LogEntry]{Count: 2 * listPageSize, Current: page}
+		if page == 1 {
+			response.Next = 2
+		}
+		// Entries are returned newest first, so IDs count down across pages.
+		for i := 0; i < listPageSize; i++ {
+			response.Results = append(response.Results, LogEntry{ID: (3-page)*listPageSize - i})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer srv.Close()
+
+	ac := &client.AlpaconClient{HTTPClient: srv.Client(), BaseURL: srv.URL}
+	entries, err := ListLogsAfter(ac, LogFilter{}, ExportCheckpoint{})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2*listPageSize)
+	assert.Equal(t, 1, entries[0].ID)
+	assert.Equal(t, []string{"1", "2"}, requested)
+}
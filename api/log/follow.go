@@ -0,0 +1,126 @@
+package log
+
+import (
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"sort"
+	"time"
+)
+
+const (
+	followPageSize = 100
+	// followMaxPages bounds how far back a single poll pages when many entries arrived at once.
+	followMaxPages = 10
+	// followMaxBackoff caps the delay between polls while the API is unreachable.
+	followMaxBackoff = 30 * time.Second
+)
+
+// FollowSystemLogs reports the log entries matching filter as they are added, oldest first, by polling every interval.
+// The last tail entries are reported first. Entries are tracked by ID, so each is reported exactly once.
+// Failed polls are retried with backoff after refreshing an expired access token,
+// so FollowSystemLogs only returns when the initial request fails.
+func FollowSystemLogs(ac *client.AlpaconClient, filter LogFilter, tail int, interval time.Duration, fn func(LogEntry)) error {
+	params, err := filterParams(ac, filter)
+	if err != nil {
+		return err
+	}
+
+	initial, _, err := fetchLogPage(ac, params, 1, followPageSize)
+	if err != nil {
+		return err
+	}
+
+	initial, cursor := entriesAfter(initial, 0)
+	initial = filter.filter(initial)
+	if len(initial) > tail {
+		initial = initial[len(initial)-tail:]
+	}
+	for _, entry := range initial {
+		fn(entry)
+	}
+
+	delay := interval
+	for {
+		time.Sleep(delay)
+
+		entries, err := fetchLogsAfter(ac, params, cursor)
+		if err != nil {
+			if delay == interval {
+				utils.CliWarning("Failed to poll logs: %s. Retrying.", err)
+			}
+			if err = ac.RefreshAccessTokenIfExpired(); err != nil {
+				utils.CliWarning("%s.", err)
+			}
+			delay *= 2
+			if delay > followMaxBackoff {
+				delay = followMaxBackoff
+			}
+			continue
+		}
+		delay = interval
+
+		entries, cursor = entriesAfter(entries, cursor)
+		for _, entry := range filter.filter(entries) {
+			fn(entry)
+		}
+	}
+}
+
+// fetchLogsAfter pages through the newest log entries until it reaches one with an ID of at most cursor.
+func fetchLogsAfter(ac *client.AlpaconClient, params map[string]string, cursor int) ([]LogEntry, error) {
+	var entries []LogEntry
+	for page, fetched := 1, 0; page != 0 && fetched < followMaxPages; fetched++ {
+		results, next, err := fetchLogPage(ac, params, page, followPageSize)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, results...)
+		page = next
+
+		if len(results) == 0 || minID(results) <= cursor {
+			break
+		}
+	}
+	return entries, nil
+}
+
+// entriesAfter returns the entries with an ID above cursor in ID order, and the highest ID seen.
+func entriesAfter(entries []LogEntry, cursor int) ([]LogEntry, int) {
+	var fresh []LogEntry
+	seen := make(map[int]bool)
+	for _, entry := range entries {
+		if entry.ID > cursor && !seen[entry.ID] {
+			seen[entry.ID] = true
+			fresh = append(fresh, entry)
+		}
+	}
+
+	sort.Slice(fresh, func(i, j int) bool {
+		return fresh[i].ID < fresh[j].ID
+	})
+	if len(fresh) > 0 {
+		cursor = fresh[len(fresh)-1].ID
+	}
+	return fresh, cursor
+}
+
+// filter returns the entries that match f, in order.
+func (f LogFilter) filter(entries []LogEntry) []LogEntry {
+	var matching []LogEntry
+	for _, entry := range entries {
+		if f.matches(entry) {
+			matching = append(matching, entry)
+		}
+	}
+	return matching
+}
+
+func minID(entries []LogEntry) int {
+	id := entries[0].ID
+	for _, entry := range entries[1:] {
+		if entry.ID < id {
+			id = entry.ID
+		}
+	}
+	return id
+}
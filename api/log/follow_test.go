@@ -0,0 +1,19 @@
+package log
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEntriesAfter(t *testing.T) {
+	// Pages overlap when entries arrive between requests, and the API returns the newest first.
+	entries := []LogEntry{{ID: 12}, {ID: 11}, {ID: 10}, {ID: 11}, {ID: 9}, {ID: 8}}
+
+	fresh, cursor := entriesAfter(entries, 9)
+	assert.Equal(t, []LogEntry{{ID: 10}, {ID: 11}, {ID: 12}}, fresh)
+	assert.Equal(t, 12, cursor)
+
+	fresh, cursor = entriesAfter(entries, cursor)
+	assert.Empty(t, fresh)
+	assert.Equal(t, 12, cursor)
+}
@@ -7,37 +7,42 @@ import (
 	"github.com/alpacanetworks/alpacon-cli/api/server"
 	"github.com/alpacanetworks/alpacon-cli/client"
 	"github.com/alpacanetworks/alpacon-cli/utils"
+	"strconv"
+	"strings"
+	"time"
 )
 
 const (
 	getSystemLogURL = "/api/history/logs/"
+	listPageSize    = 100
+	// grepMaxPages bounds how far back a regular expression is matched when no start time is given,
+	// as the API cannot filter by it and every entry has to be fetched.
+	grepMaxPages = 50
 )
 
-func GetSystemLogList(ac *client.AlpaconClient, serverName string, pageSize int) ([]LogAttributes, error) {
-	serverID, err := server.GetServerIDByName(ac, serverName)
-	if err != nil {
-		return nil, err
-	}
+var logLevels = map[string]int{
+	"DEBUG":    10,
+	"INFO":     20,
+	"WARN":     30,
+	"WARNING":  30,
+	"ERROR":    40,
+	"CRITICAL": 50,
+}
 
-	params := map[string]string{
-		"server":    serverID,
-		"page_size": fmt.Sprintf("%d", pageSize),
-	}
-	responseBody, err := ac.SendGetRequest(utils.BuildURL(getSystemLogURL, "", params))
+// GetSystemLogList returns the newest log entries matching filter, newest first.
+// A pageSize of 0 returns every matching entry.
+func GetSystemLogList(ac *client.AlpaconClient, pageSize int, filter LogFilter) ([]LogAttributes, error) {
+	entries, err := ListLogs(ac, filter, pageSize)
 	if err != nil {
 		return nil, err
 	}
 
-	var response api.ListResponse[LogEntry]
-	if err = json.Unmarshal(responseBody, &response); err != nil {
-		return nil, err
-	}
-
 	var logList []LogAttributes
-	for _, log := range response.Results {
+	for _, log := range entries {
 		logList = append(logList, LogAttributes{
+			Server:  log.ServerName,
 			Program: log.Program,
-			Level:   getLogLevel(log.Level),
+			Level:   LevelName(log.Level),
 			Message: fmt.Sprintf("[%s] %s", log.Process, log.Msg),
 			//	Date:    log.Date.Format("2006-01-02 15:04:05 MST"),
 			Date: utils.TimeUtils(log.Date),
@@ -47,7 +52,175 @@ func GetSystemLogList(ac *client.AlpaconClient, serverName string, pageSize int)
 	return logList, nil
 }
 
-func getLogLevel(level int) string {
+// ListLogs pages through the log entries matching filter, newest first, until limit entries are found
+// or the entries become older than filter.Since. A limit of 0 means no limit.
+// Filters are sent as query parameters where the API supports them and are always applied locally,
+// so every returned entry matches. Without filter.Since, filter.Grep is only matched against the newest
+// grepMaxPages pages of entries.
+func ListLogs(ac *client.AlpaconClient, filter LogFilter, limit int) ([]LogEntry, error) {
+	params, err := filterParams(ac, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize := listPageSize
+	if limit > 0 && limit < pageSize && filter.Grep == nil {
+		pageSize = limit
+	}
+
+	var entries []LogEntry
+	for page, fetched := 1, 0; page != 0; fetched++ {
+		if filter.Grep != nil && filter.Since.IsZero() && fetched == grepMaxPages {
+			utils.CliWarning("Stopped searching after the newest %d log entries. Use --since to search further back.", grepMaxPages*pageSize)
+			break
+		}
+
+		results, next, err := fetchLogPage(ac, params, page, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		page = next
+
+		for _, entry := range results {
+			if filter.matches(entry) {
+				entries = append(entries, entry)
+				if limit > 0 && len(entries) == limit {
+					return entries, nil
+				}
+			}
+		}
+
+		if len(results) == 0 {
+			break
+		}
+		if oldest := results[len(results)-1]; !filter.Since.IsZero() && oldest.Date.Before(filter.Since) {
+			break
+		}
+	}
+	return entries, nil
+}
+
+// filterParams maps filter to the query parameters of the logs API. The regular expression has no equivalent.
+func filterParams(ac *client.AlpaconClient, filter LogFilter) (map[string]string, error) {
+	params := map[string]string{}
+	if filter.ServerName != "" {
+		serverID, err := server.GetServerIDByName(ac, filter.ServerName)
+		if err != nil {
+			return nil, err
+		}
+		params["server"] = serverID
+	}
+	if filter.MinLevel > 0 {
+		params["level__gte"] = strconv.Itoa(filter.MinLevel)
+	}
+	if filter.MaxLevel > 0 {
+		params["level__lte"] = strconv.Itoa(filter.MaxLevel)
+	}
+	if filter.Program != "" {
+		params["program"] = filter.Program
+	}
+	if !filter.Since.IsZero() {
+		params["date__gte"] = filter.Since.UTC().Format(time.RFC3339)
+	}
+	if !filter.Until.IsZero() {
+		params["date__lte"] = filter.Until.UTC().Format(time.RFC3339)
+	}
+	if filter.Process != "" {
+		params["process"] = filter.Process
+	}
+	if filter.Thread != "" {
+		params["thread"] = filter.Thread
+	}
+	return params, nil
+}
+
+// fetchLogPage returns one page of the newest log entries matching params,
+// and the number of the next page, which is 0 after the last one.
+func fetchLogPage(ac *client.AlpaconClient, params map[string]string, page, pageSize int) ([]LogEntry, int, error) {
+	query := map[string]string{
+		"page":      strconv.Itoa(page),
+		"page_size": strconv.Itoa(pageSize),
+	}
+	for key, value := range params {
+		query[key] = value
+	}
+
+	responseBody, err := ac.SendGetRequest(utils.BuildURL(getSystemLogURL, "", query))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var response api.ListResponse[LogEntry]
+	if err = json.Unmarshal(responseBody, &response); err != nil {
+		return nil, 0, err
+	}
+	return response.Results, response.Next, nil
+}
+
+// matches reports whether entry satisfies filter.
+func (f LogFilter) matches(entry LogEntry) bool {
+	if f.MinLevel > 0 && entry.Level < f.MinLevel {
+		return false
+	}
+	if f.MaxLevel > 0 && entry.Level > f.MaxLevel {
+		return false
+	}
+	if f.Program != "" && entry.Program != f.Program {
+		return false
+	}
+	if !f.Since.IsZero() && entry.Date.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && entry.Date.After(f.Until) {
+		return false
+	}
+	if f.Process != "" && entry.Process != f.Process {
+		return false
+	}
+	if f.Thread != "" && entry.Thread != f.Thread {
+		return false
+	}
+	if f.Grep != nil && !f.Grep.MatchString(entry.Msg) {
+		return false
+	}
+	return true
+}
+
+// ParseLevelRange parses a level condition such as WARN, >=WARN, <INFO, =ERROR or >=30 into inclusive bounds,
+// where 0 means unbounded. A level without an operator selects that level and above, like journalctl -p.
+func ParseLevelRange(value string) (min, max int, err error) {
+	op := ">="
+	for _, prefix := range []string{">=", "<=", ">", "<", "="} {
+		if strings.HasPrefix(value, prefix) {
+			op, value = prefix, value[len(prefix):]
+			break
+		}
+	}
+
+	level, ok := logLevels[strings.ToUpper(strings.TrimSpace(value))]
+	if !ok {
+		level, err = strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || level <= 0 {
+			return 0, 0, fmt.Errorf("unknown log level '%s'; use DEBUG, INFO, WARN, ERROR, CRITICAL or a number", value)
+		}
+	}
+
+	switch op {
+	case ">=":
+		return level, 0, nil
+	case ">":
+		return level + 1, 0, nil
+	case "<=":
+		return 0, level, nil
+	case "<":
+		return 0, level - 1, nil
+	default:
+		return level, level, nil
+	}
+}
+
+// LevelName returns the name of a numeric log level, as used by Python logging.
+func LevelName(level int) string {
 	switch level {
 	case 10:
 		return "DEBUG"
@@ -0,0 +1,60 @@
+package log
+
+import (
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestParseLevelRange(t *testing.T) {
+	tests := []struct {
+		value    string
+		min, max int
+	}{
+		{"WARN", 30, 0},
+		{">=warning", 30, 0},
+		{">ERROR", 41, 0},
+		{"<=INFO", 0, 20},
+		{"<INFO", 0, 19},
+		{"=DEBUG", 10, 10},
+		{">=40", 40, 0},
+	}
+	for _, tt := range tests {
+		min, max, err := ParseLevelRange(tt.value)
+		assert.NoError(t, err, tt.value)
+		assert.Equal(t, tt.min, min, tt.value)
+		assert.Equal(t, tt.max, max, tt.value)
+	}
+
+	_, _, err := ParseLevelRange(">=LOUD")
+	assert.Error(t, err)
+}
+
+func TestLogFilterMatches(t *testing.T) {
+	base := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	entry := LogEntry{
+		Date:    base,
+		Level:   30,
+		Program: "alpamon",
+		Process: "MainProcess",
+		Thread:  "MainThread",
+		Msg:     "connection refused by 10.0.0.1",
+	}
+
+	assert.True(t, LogFilter{}.matches(entry))
+	assert.True(t, LogFilter{MinLevel: 30}.matches(entry))
+	assert.False(t, LogFilter{MinLevel: 40}.matches(entry))
+	assert.False(t, LogFilter{MaxLevel: 20}.matches(entry))
+
+	assert.True(t, LogFilter{Since: base.Add(-time.Hour), Until: base.Add(time.Hour)}.matches(entry))
+	assert.False(t, LogFilter{Since: base.Add(time.Minute)}.matches(entry))
+	assert.False(t, LogFilter{Until: base.Add(-time.Minute)}.matches(entry))
+
+	assert.True(t, LogFilter{Program: "alpamon", Process: "MainProcess", Thread: "MainThread"}.matches(entry))
+	assert.False(t, LogFilter{Program: "sshd"}.matches(entry))
+	assert.False(t, LogFilter{Thread: "Worker-1"}.matches(entry))
+
+	assert.True(t, LogFilter{Grep: regexp.MustCompile(`timeout|refused`)}.matches(entry))
+	assert.False(t, LogFilter{Grep: regexp.MustCompile(`^timeout`)}.matches(entry))
+}
@@ -1,14 +1,31 @@
 package log
 
-import "time"
+import (
+	"regexp"
+	"time"
+)
 
 type LogAttributes struct {
+	Server  string `json:"server"`
 	Program string `json:"program"`
 	Level   string `json:"level"`
 	Message string `json:"message"`
 	Date    string `json:"date"`
 }
 
+// LogFilter selects log entries. Zero values do not filter.
+type LogFilter struct {
+	ServerName string
+	MinLevel   int
+	MaxLevel   int
+	Program    string
+	Since      time.Time
+	Until      time.Time
+	Process    string
+	Thread     string
+	Grep       *regexp.Regexp // matched against the message
+}
+
 type LogEntry struct {
 	ID         int       `json:"id"`
 	AddedAt    time.Time `json:"added_at"`
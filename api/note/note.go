@@ -11,7 +11,8 @@ import (
 )
 
 const (
-	noteURL = "/api/servers/notes/"
+	noteURL      = "/api/servers/notes/"
+	listPageSize = 100
 )
 
 func GetNoteList(ac *client.AlpaconClient, serverName string, pageSize int) ([]NoteDetails, error) {
@@ -63,6 +64,64 @@ func GetNoteList(ac *client.AlpaconClient, serverName string, pageSize int) ([]N
 	return noteList, nil
 }
 
+// ListServerNotes returns up to limit notes of serverName, paging through the notes until enough are found.
+// The server filter is sent to the API and checked again on each note.
+func ListServerNotes(ac *client.AlpaconClient, serverName string, limit int) ([]NoteDetails, error) {
+	serverID, err := server.GetServerIDByName(ac, serverName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Most notes of a server are written by a few users, so each author is only looked up once.
+	authors := map[string]string{}
+	var noteList []NoteDetails
+	for page := 1; page != 0 && len(noteList) < limit; {
+		params := map[string]string{
+			"server":    serverID,
+			"page":      fmt.Sprintf("%d", page),
+			"page_size": fmt.Sprintf("%d", listPageSize),
+		}
+		responseBody, err := ac.SendGetRequest(utils.BuildURL(noteURL, "", params))
+		if err != nil {
+			return nil, err
+		}
+
+		var response api.ListResponse[NoteDetails]
+		if err = json.Unmarshal(responseBody, &response); err != nil {
+			return nil, err
+		}
+		page = response.Next
+
+		for _, note := range response.Results {
+			if note.Server != serverID {
+				continue
+			}
+
+			userName, ok := authors[note.Author]
+			if !ok {
+				userName, err = iam.GetUserNameByID(ac, note.Author)
+				if err != nil {
+					return nil, err
+				}
+				authors[note.Author] = userName
+			}
+
+			noteList = append(noteList, NoteDetails{
+				ID:      note.ID,
+				Server:  serverName,
+				Author:  userName,
+				Content: note.Content,
+				Private: note.Private,
+			})
+			if len(noteList) == limit {
+				break
+			}
+		}
+	}
+
+	return noteList, nil
+}
+
 func CreateNote(ac *client.AlpaconClient, noteRequest NoteCreateRequest) error {
 	serverID, err := server.GetServerIDByName(ac, noteRequest.Server)
 	if err != nil {
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"strings"
+)
+
+// ServerSpec describes a server to create. Groups are given by name.
+type ServerSpec struct {
+	Name     string   `yaml:"name"`
+	Platform string   `yaml:"platform"`
+	Groups   []string `yaml:"groups"`
+}
+
+// Validate checks that the spec names the server, a supported platform and at least one group.
+func (s ServerSpec) Validate() error {
+	if s.Name == "" {
+		return errors.New("the server name is required")
+	}
+	if s.Platform != "debian" && s.Platform != "rhel" {
+		return fmt.Errorf("invalid platform '%s' for %s; choose debian or rhel", s.Platform, s.Name)
+	}
+	if len(s.Groups) == 0 {
+		return fmt.Errorf("at least one group must be authorized to access %s", s.Name)
+	}
+	return nil
+}
+
+// ParseManifest reads the servers described by a YAML manifest. The manifest is either a single server,
+// a list of servers, or a mapping with a servers list:
+//
+//	servers:
+//	  - name: web-01
+//	    platform: debian
+//	    groups: [admin, developers]
+func ParseManifest(data []byte) ([]ServerSpec, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	if len(root.Content) == 0 {
+		return nil, errors.New("the manifest is empty")
+	}
+
+	var specs []ServerSpec
+	doc := root.Content[0]
+	switch {
+	case doc.Kind == yaml.SequenceNode:
+		if err := decodeStrict(doc, &specs); err != nil {
+			return nil, err
+		}
+	case doc.Kind == yaml.MappingNode && hasKey(doc, "servers"):
+		var manifest struct {
+			Servers []ServerSpec `yaml:"servers"`
+		}
+		if err := decodeStrict(doc, &manifest); err != nil {
+			return nil, err
+		}
+		specs = manifest.Servers
+	default:
+		var spec ServerSpec
+		if err := decodeStrict(doc, &spec); err != nil {
+			return nil, err
+		}
+		specs = append(specs, spec)
+	}
+
+	if len(specs) == 0 {
+		return nil, errors.New("the manifest describes no servers")
+	}
+	seen := map[string]bool{}
+	for i := range specs {
+		specs[i].Platform = strings.ToLower(specs[i].Platform)
+		if err := specs[i].Validate(); err != nil {
+			return nil, err
+		}
+		if seen[specs[i].Name] {
+			return nil, fmt.Errorf("the server %s is described more than once", specs[i].Name)
+		}
+		seen[specs[i].Name] = true
+	}
+	return specs, nil
+}
+
+// decodeStrict decodes node into v, rejecting unknown fields so that typos do not go unnoticed.
+func decodeStrict(node *yaml.Node, v interface{}) error {
+	out, err := yaml.Marshal(node)
+	if err != nil {
+		return err
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(out))
+	decoder.KnownFields(true)
+	if err = decoder.Decode(v); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+func hasKey(node *yaml.Node, key string) bool {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseManifest(t *testing.T) {
+	expected := []ServerSpec{
+		{Name: "web-01", Platform: "debian", Groups: []string{"admin", "developers"}},
+		{Name: "db-01", Platform: "rhel", Groups: []string{"admin"}},
+	}
+
+	specs, err := ParseManifest([]byte(`
+servers:
+  - name: web-01
+    platform: debian
+    groups: [admin, developers]
+  - name: db-01
+    platform: RHEL
+    groups:
+      - admin
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, specs)
+
+	specs, err = ParseManifest([]byte(`
+- {name: web-01, platform: debian, groups: [admin, developers]}
+- {name: db-01, platform: rhel, groups: [admin]}
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, specs)
+
+	specs, err = ParseManifest([]byte("name: db-01\nplatform: rhel\ngroups: [admin]\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, expected[1:], specs)
+}
+
+func TestParseManifestErrors(t *testing.T) {
+	for _, manifest := range []string{
+		"",
+		"servers: []",
+		"name: web-01\nplatform: windows\ngroups: [admin]",
+		"name: web-01\nplatform: debian",
+		"name: web-01\nplatfrom: debian\ngroups: [admin]",
+		"- {name: web-01, platform: debian, groups: [admin]}\n- {name: web-01, platform: rhel, groups: [admin]}",
+	} {
+		_, err := ParseManifest([]byte(manifest))
+		assert.Error(t, err, manifest)
+	}
+}
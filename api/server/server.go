@@ -15,15 +15,34 @@ const (
 )
 
 func GetServerList(ac *client.AlpaconClient) ([]ServerAttributes, error) {
+	servers, err := ListServers(ac)
+	if err != nil {
+		return nil, err
+	}
+
 	var serverList []ServerAttributes
-	page := 1
+	for _, server := range servers {
+		serverList = append(serverList, ServerAttributes{
+			Name:      server.Name,
+			IP:        server.RemoteIP,
+			OS:        fmt.Sprintf("%s %s", server.OSName, server.OSVersion),
+			Connected: server.IsConnected,
+			Owner:     server.OwnerName,
+		})
+	}
+	return serverList, nil
+}
+
+// ListServers returns the details of every server.
+func ListServers(ac *client.AlpaconClient) ([]ServerDetails, error) {
+	var servers []ServerDetails
 	const pageSize = 100
 
-	params := map[string]string{
-		"page":      strconv.Itoa(page),
-		"page_size": fmt.Sprintf("%d", pageSize),
-	}
-	for {
+	for page := 1; ; page++ {
+		params := map[string]string{
+			"page":      strconv.Itoa(page),
+			"page_size": fmt.Sprintf("%d", pageSize),
+		}
 		responseBody, err := ac.SendGetRequest(utils.BuildURL(serverURL, "", params))
 		if err != nil {
 			return nil, err
@@ -33,24 +52,13 @@ func GetServerList(ac *client.AlpaconClient) ([]ServerAttributes, error) {
 		if err = json.Unmarshal(responseBody, &response); err != nil {
 			return nil, err
 		}
-
-		for _, server := range response.Results {
-			serverList = append(serverList, ServerAttributes{
-				Name:      server.Name,
-				IP:        server.RemoteIP,
-				OS:        fmt.Sprintf("%s %s", server.OSName, server.OSVersion),
-				Connected: server.IsConnected,
-				Owner:     server.OwnerName,
-			})
-		}
+		servers = append(servers, response.Results...)
 
 		if len(response.Results) < pageSize {
 			break
 		}
-		page++
 	}
-
-	return serverList, nil
+	return servers, nil
 }
 
 func GetServerDetail(ac *client.AlpaconClient, serverName string) ([]byte, error) {
@@ -67,6 +75,18 @@ func GetServerDetail(ac *client.AlpaconClient, serverName string) ([]byte, error
 	return body, nil
 }
 
+// GetServer returns the details of serverName.
+func GetServer(ac *client.AlpaconClient, serverName string) (ServerDetails, error) {
+	var details ServerDetails
+	responseBody, err := GetServerDetail(ac, serverName)
+	if err != nil {
+		return details, err
+	}
+
+	err = json.Unmarshal(responseBody, &details)
+	return details, err
+}
+
 func DeleteServer(ac *client.AlpaconClient, serverName string) error {
 	serverID, err := GetServerIDByName(ac, serverName)
 	if err != nil {
@@ -81,6 +101,36 @@ func DeleteServer(ac *client.AlpaconClient, serverName string) error {
 	return nil
 }
 
+// UpdateServer opens the details of serverName in an editor and applies the changes.
+func UpdateServer(ac *client.AlpaconClient, serverName string) ([]byte, error) {
+	serverID, err := GetServerIDByName(ac, serverName)
+	if err != nil {
+		return nil, err
+	}
+
+	responseBody, err := ac.SendGetRequest(utils.BuildURL(serverURL, serverID, nil))
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := utils.ProcessEditedData(responseBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return ac.SendPatchRequest(utils.BuildURL(serverURL, serverID, nil), data)
+}
+
+// PatchServer applies updateRequest to serverName.
+func PatchServer(ac *client.AlpaconClient, serverName string, updateRequest ServerUpdateRequest) ([]byte, error) {
+	serverID, err := GetServerIDByName(ac, serverName)
+	if err != nil {
+		return nil, err
+	}
+
+	return ac.SendPatchRequest(utils.BuildURL(serverURL, serverID, nil), updateRequest)
+}
+
 func GetServerIDByName(ac *client.AlpaconClient, serverName string) (string, error) {
 	params := map[string]string{
 		"name": serverName,
@@ -16,6 +16,13 @@ type ServerRequest struct {
 	Groups   []string `json:"groups"`
 }
 
+// ServerUpdateRequest holds the fields to change on a server. Nil and empty fields are left unchanged.
+type ServerUpdateRequest struct {
+	Name    *string  `json:"name,omitempty"`
+	Groups  []string `json:"groups,omitempty"`
+	Starred *bool    `json:"starred,omitempty"`
+}
+
 type ServerCreatedResponse struct {
 	Name         string   `json:"name"`
 	ID           string   `json:"id"`
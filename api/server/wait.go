@@ -0,0 +1,75 @@
+package server
+
+import (
+	"fmt"
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"time"
+)
+
+// WaitConditions lists the conditions understood by WaitForServer.
+var WaitConditions = []string{"connected", "commissioned"}
+
+// WaitForServer polls serverName every interval until it meets condition (connected or commissioned)
+// and returns its details. onChange is called with the first details and whenever the connection,
+// commissioning or status text changes. Failed polls, for instance before the server is registered,
+// are retried until the timeout.
+func WaitForServer(ac *client.AlpaconClient, serverName, condition string, timeout, interval time.Duration, onChange func(ServerDetails)) (ServerDetails, error) {
+	if !validCondition(condition) {
+		return ServerDetails{}, fmt.Errorf("unknown condition '%s'; choose connected or commissioned", condition)
+	}
+
+	deadline := time.Now().Add(timeout)
+	var last ServerDetails
+	var lastErr error
+	polled := false
+	for {
+		details, err := GetServer(ac, serverName)
+		if err != nil {
+			if lastErr == nil || lastErr.Error() != err.Error() {
+				utils.CliWarning("Failed to get the server %s: %s. Retrying.", serverName, err)
+			}
+			lastErr = err
+			if refreshErr := ac.RefreshAccessTokenIfExpired(); refreshErr != nil {
+				utils.CliWarning("%s.", refreshErr)
+			}
+		} else {
+			lastErr = nil
+			if !polled || stateChanged(last, details) {
+				onChange(details)
+			}
+			last, polled = details, true
+			if meets(details, condition) {
+				return details, nil
+			}
+		}
+
+		if time.Now().Add(interval).After(deadline) {
+			if lastErr != nil {
+				return last, fmt.Errorf("timed out after %s waiting for %s to be %s: %v", timeout, serverName, condition, lastErr)
+			}
+			return last, fmt.Errorf("timed out after %s waiting for %s to be %s", timeout, serverName, condition)
+		}
+		time.Sleep(interval)
+	}
+}
+
+func validCondition(condition string) bool {
+	for _, c := range WaitConditions {
+		if c == condition {
+			return true
+		}
+	}
+	return false
+}
+
+func meets(details ServerDetails, condition string) bool {
+	if condition == "commissioned" {
+		return details.Commissioned
+	}
+	return details.IsConnected
+}
+
+func stateChanged(a, b ServerDetails) bool {
+	return a.IsConnected != b.IsConnected || a.Commissioned != b.Commissioned || a.Status.Text != b.Status.Text
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"encoding/json"
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWaitForServer(t *testing.T) {
+	states := []ServerDetails{
+		{ID: "s1", Name: "web", Status: ServerStatus{Text: "Not connected"}},
+		{ID: "s1", Name: "web", Status: ServerStatus{Text: "Not connected"}},
+		{ID: "s1", Name: "web", IsConnected: true, Status: ServerStatus{Text: "Connected"}},
+	}
+	polls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("name") != "" {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"count": 1, "results": []ServerDetails{states[0]}})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(states[polls])
+		if polls < len(states)-1 {
+			polls++
+		}
+	}))
+	defer srv.Close()
+
+	ac := &client.AlpaconClient{HTTPClient: srv.Client(), BaseURL: srv.URL}
+	var changes []string
+	details, err := WaitForServer(ac, "web", "connected", time.Second, time.Millisecond, func(d ServerDetails) {
+		changes = append(changes, d.Status.Text)
+	})
+	assert.NoError(t, err)
+	assert.True(t, details.IsConnected)
+	assert.Equal(t, []string{"Not connected", "Connected"}, changes)
+
+	polls = 0
+	_, err = WaitForServer(ac, "web", "commissioned", 5*time.Millisecond, time.Millisecond, func(ServerDetails) {})
+	assert.ErrorContains(t, err, "timed out")
+
+	_, err = WaitForServer(ac, "web", "online", time.Second, time.Millisecond, func(ServerDetails) {})
+	assert.Error(t, err)
+}
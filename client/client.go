@@ -43,6 +43,8 @@ func NewAlpaconAPIClient() (*AlpaconClient, error) {
 		Token:       validConfig.Token,
 		AccessToken: validConfig.AccessToken,
 		UserAgent:   utils.GetUserAgent(),
+
+		accessTokenExpiresAt: accessTokenExpiry(validConfig),
 	}
 
 	if isAccessTokenExpired(validConfig) {
@@ -52,7 +54,7 @@ func NewAlpaconAPIClient() (*AlpaconClient, error) {
 			return nil, fmt.Errorf("failed to refresh access token: %v", err)
 		}
 
-		client.AccessToken = tokenRes.AccessToken
+		client.setAccessToken(tokenRes)
 	}
 
 	err = client.checkAuth()
@@ -68,6 +70,43 @@ func NewAlpaconAPIClient() (*AlpaconClient, error) {
 	return client, nil
 }
 
+// RefreshAccessTokenIfExpired renews the access token once it has expired, so that long-running commands keep working.
+// The expiry is kept on the client, so the config is only read again once the token is about to expire.
+// Clients authenticated with an API token are left unchanged.
+func (ac *AlpaconClient) RefreshAccessTokenIfExpired() error {
+	if ac.AccessToken == "" {
+		return nil
+	}
+	if !ac.accessTokenExpiresAt.IsZero() && time.Now().Before(ac.accessTokenExpiresAt.Add(-10*time.Second)) {
+		return nil
+	}
+
+	validConfig, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	if !isAccessTokenExpired(validConfig) {
+		// Another alpacon process may have refreshed the token already.
+		ac.AccessToken = validConfig.AccessToken
+		ac.accessTokenExpiresAt = accessTokenExpiry(validConfig)
+		return nil
+	}
+
+	tokenRes, err := auth0.RefreshAccessToken(validConfig.WorkspaceURL, ac.HTTPClient, validConfig.RefreshToken)
+	if err != nil {
+		return fmt.Errorf("failed to refresh access token: %v", err)
+	}
+	ac.setAccessToken(tokenRes)
+	return nil
+}
+
+// setAccessToken keeps a refreshed token and its expiry. auth0.RefreshAccessToken saves both to the config as well.
+func (ac *AlpaconClient) setAccessToken(tokenRes *auth0.TokenResponse) {
+	ac.AccessToken = tokenRes.AccessToken
+	ac.accessTokenExpiresAt = time.Now().Add(time.Duration(tokenRes.ExpiresIn) * time.Second)
+}
+
 func (ac *AlpaconClient) checkAuth() error {
 	body, err := ac.SendGetRequest(checkAuthURL)
 	if err != nil {
@@ -200,6 +239,15 @@ func (ac *AlpaconClient) SendPostRequest(url string, body interface{}) ([]byte,
 	return ac.sendRequest(req)
 }
 
+// OPTIONS Request to Alpacon Server, which describes the fields an endpoint accepts
+func (ac *AlpaconClient) SendOptionsRequest(url string) ([]byte, error) {
+	req, err := ac.createRequest(http.MethodOptions, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return ac.sendRequest(req)
+}
+
 func (ac *AlpaconClient) SendDeleteRequest(url string) ([]byte, error) {
 	req, err := ac.createRequest(http.MethodDelete, url, nil)
 	if err != nil {
@@ -281,6 +329,15 @@ func (ac *AlpaconClient) IsUsingHTTPS() (bool, error) {
 	return false, nil
 }
 
+// accessTokenExpiry returns when the access token of cfg expires, or zero if that is unknown.
+func accessTokenExpiry(cfg config.Config) time.Time {
+	expireTime, err := time.Parse(time.RFC3339, cfg.AccessTokenExpiresAt)
+	if err != nil {
+		return time.Time{}
+	}
+	return expireTime
+}
+
 func isAccessTokenExpired(cfg config.Config) bool {
 	if cfg.AccessToken == "" {
 		return false
@@ -1,6 +1,9 @@
 package client
 
-import "net/http"
+import (
+	"net/http"
+	"time"
+)
 
 type AlpaconClient struct {
 	HTTPClient  *http.Client
@@ -9,6 +12,9 @@ type AlpaconClient struct {
 	AccessToken string
 	Privileges  string
 	UserAgent   string
+
+	// accessTokenExpiresAt is when AccessToken expires, or zero if that is unknown.
+	accessTokenExpiresAt time.Time
 }
 
 type CheckAuthResponse struct {
@@ -1,12 +1,16 @@
 package event
 
 import (
+	"fmt"
 	"github.com/alpacanetworks/alpacon-cli/api/event"
 	"github.com/alpacanetworks/alpacon-cli/client"
 	"github.com/alpacanetworks/alpacon-cli/utils"
 	"github.com/spf13/cobra"
+	"time"
 )
 
+const followInterval = 2 * time.Second
+
 var EventCmd = &cobra.Command{
 	Use:     "event",
 	Aliases: []string{"events"},
@@ -15,12 +19,23 @@ var EventCmd = &cobra.Command{
 	Retrieve and display a list of recent events from the Alpacon, with options to filter by server, user, and the number of events. 
 	Use the '--tail' flag to limit the output to the last N event entries. 
 	Specify a server with '--server' or filter events by user with '--user' to narrow down the results.
+	Narrow the history down with '--since' and '--until', which take an age such as 2h or 7d, a date, or an RFC3339 timestamp,
+	and with '--status', '--shell' and '--command-contains'. Use '--tail 0' to page through every matching event.
+	Use the '--follow' flag to keep watching for new commands as they are requested.
+	It starts with the last '--tail' events; '--tail 0' shows only new ones.
 	`,
 	Example: `
 	alpacon event
 	alpacon events
 	alpacon event -tail 10 -s myserver -u admin
 	alpacon event --tail=10 --server=myserver --user=admin
+	alpacon event --since 2h --status failed
+	alpacon event --since 2024-03-01 --until 2024-03-08 --command-contains systemctl --tail 0
+	alpacon event --follow
+	alpacon event -f -s myserver
+	alpacon event describe [EVENT ID]
+	alpacon event rerun [EVENT ID]
+	alpacon event export --format csv -o events.csv
 	`,
 	Run: runEvent,
 }
@@ -30,15 +45,74 @@ func init() {
 	var serverName string
 	var userName string
 
-	EventCmd.Flags().IntVarP(&pageSize, "tail", "t", 25, "Number of event entries to show from the end")
+	EventCmd.Flags().IntVarP(&pageSize, "tail", "t", 25, "Number of event entries to show from the end (0 for all, or only new events with --follow)")
 	EventCmd.Flags().StringVarP(&serverName, "server", "s", "", "Specify server for events")
 	EventCmd.Flags().StringVarP(&userName, "user", "u", "", "Specify request user for events")
+	EventCmd.Flags().BoolP("follow", "f", false, "Keep streaming new events as they are requested")
+	addFilterFlags(EventCmd)
+
+	EventCmd.AddCommand(eventDescribeCmd)
+	EventCmd.AddCommand(eventRerunCmd)
+	EventCmd.AddCommand(eventReportCmd)
+	EventCmd.AddCommand(eventExportCmd)
+}
+
+// addFilterFlags adds the time, status, shell and command filters shared by the event commands.
+func addFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().String("since", "", "Show events requested after this time (e.g. 2h, 7d, 2006-01-02 or RFC3339)")
+	cmd.Flags().String("until", "", "Show events requested before this time (e.g. 1h, 2006-01-02 or RFC3339)")
+	cmd.Flags().String("status", "", "Show only events with this status: success, failed or stuck")
+	cmd.Flags().String("shell", "", "Show only events run in this shell (e.g. system, osquery)")
+	cmd.Flags().String("command-contains", "", "Show only events whose command contains this text")
+}
+
+// parseFilter builds an event filter from the flags added by addFilterFlags and the server and user names.
+func parseFilter(cmd *cobra.Command, serverName, userName string) event.EventFilter {
+	since, _ := cmd.Flags().GetString("since")
+	until, _ := cmd.Flags().GetString("until")
+	status, _ := cmd.Flags().GetString("status")
+	shell, _ := cmd.Flags().GetString("shell")
+	commandContains, _ := cmd.Flags().GetString("command-contains")
+
+	filter := event.EventFilter{
+		ServerName:      serverName,
+		UserName:        userName,
+		Status:          status,
+		Shell:           shell,
+		CommandContains: commandContains,
+	}
+
+	now := time.Now()
+	var err error
+	if since != "" {
+		if filter.Since, err = utils.ParseTimeArg(since, now); err != nil {
+			utils.CliError("Invalid --since: %s.", err)
+		}
+	}
+	if until != "" {
+		if filter.Until, err = utils.ParseTimeArg(until, now); err != nil {
+			utils.CliError("Invalid --until: %s.", err)
+		}
+	}
+
+	switch status {
+	case "", "success", "failed", "stuck":
+	default:
+		utils.CliError("Invalid status '%s'. Choose one of success, failed or stuck.", status)
+	}
+	return filter
 }
 
 func runEvent(cmd *cobra.Command, args []string) {
 	pageSize, _ := cmd.Flags().GetInt("tail")
 	serverName, _ := cmd.Flags().GetString("server")
 	userName, _ := cmd.Flags().GetString("user")
+	follow, _ := cmd.Flags().GetBool("follow")
+	filter := parseFilter(cmd, serverName, userName)
+
+	if pageSize < 0 {
+		utils.CliError("The number of events must not be negative.")
+	}
 
 	alpaconClient, err := client.NewAlpaconAPIClient()
 	if err != nil {
@@ -46,7 +120,15 @@ func runEvent(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	eventList, err := event.GetEventList(alpaconClient, pageSize, serverName, userName)
+	if follow {
+		err = event.FollowEvents(alpaconClient, filter, pageSize, followInterval, printEvent)
+		if err != nil {
+			utils.CliError("Failed to follow events: %s.", err)
+		}
+		return
+	}
+
+	eventList, err := event.GetEventList(alpaconClient, pageSize, filter)
 	if err != nil {
 		utils.CliError("Failed to get events: %s.", err)
 		return
@@ -54,3 +136,9 @@ func runEvent(cmd *cobra.Command, args []string) {
 
 	utils.PrintTable(eventList)
 }
+
+// printEvent prints a single event as one line, for use in follow mode.
+func printEvent(e event.EventDetails) {
+	fmt.Printf("%s  %s  %s  %s@%s  %s  %s\n",
+		e.AddedAt.Local().Format("2006-01-02 15:04:05"), e.ID, eventStatus(e), e.RequestedByName, e.ServerName, e.Shell, e.Line)
+}
@@ -0,0 +1,101 @@
+package event
+
+import (
+	"fmt"
+	"github.com/alpacanetworks/alpacon-cli/api/event"
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"github.com/spf13/cobra"
+	"sort"
+	"strings"
+	"time"
+)
+
+var eventDescribeCmd = &cobra.Command{
+	Use:     "describe [EVENT ID]",
+	Aliases: []string{"desc"},
+	Short:   "Display the full details and output of an event",
+	Long: `
+	The describe command shows everything recorded for a command event: the command, the server,
+	who requested it and as which user it ran, its environment, timings, status and the complete output.
+	`,
+	Example: `
+	alpacon event describe 7c8b3a1e-52f4-4f8e-9d1b-0f1e2d3c4b5a
+	`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		alpaconClient, err := client.NewAlpaconAPIClient()
+		if err != nil {
+			utils.CliError("Connection to Alpacon API failed: %s. Consider re-logging.", err)
+		}
+
+		eventDetail, err := event.GetEventDetail(alpaconClient, args[0])
+		if err != nil {
+			utils.CliError("Failed to retrieve the event details: %s.", err)
+		}
+
+		printEventDetail(eventDetail)
+	},
+}
+
+// printEventDetail prints every field of e, followed by its untruncated result.
+func printEventDetail(e event.EventDetails) {
+	runAs := e.Username
+	if runAs == "" {
+		runAs = "(default)"
+	}
+	if e.Groupname != "" {
+		runAs += ":" + e.Groupname
+	}
+
+	var env []string
+	for key, value := range e.Env {
+		env = append(env, key+"="+value)
+	}
+	sort.Strings(env)
+
+	fields := [][2]string{
+		{"ID", e.ID},
+		{"Server", e.ServerName},
+		{"Shell", e.Shell},
+		{"Command", e.Line},
+		{"Run as", runAs},
+		{"Requested by", e.RequestedByName},
+		{"Requested at", fmt.Sprintf("%s (%s)", e.AddedAt.Local().Format(time.RFC3339), utils.TimeUtils(e.AddedAt))},
+		{"Status", eventStatus(e)},
+		{"Status message", statusMessage(e)},
+		{"Response delay", fmt.Sprintf("%.3fs", e.ResponseDelay)},
+		{"Elapsed time", fmt.Sprintf("%.3fs", e.ElapsedTime)},
+		{"Env", strings.Join(env, " ")},
+	}
+	for _, field := range fields {
+		if field[1] == "" {
+			continue
+		}
+		fmt.Printf("%-16s%s\n", field[0]+":", field[1])
+	}
+
+	fmt.Println()
+	utils.PrintHeader("Result")
+	fmt.Println(strings.TrimRight(e.Result, "\n"))
+}
+
+// eventStatus describes the outcome of e: success or failed once it has finished, otherwise its status text.
+func eventStatus(e event.EventDetails) string {
+	switch {
+	case e.Success == nil:
+		if text, ok := e.Status["text"].(string); ok && text != "" {
+			return text
+		}
+		return "pending"
+	case *e.Success:
+		return utils.Green("success")
+	default:
+		return utils.Red("failed")
+	}
+}
+
+func statusMessage(e event.EventDetails) string {
+	message, _ := e.Status["message"].(string)
+	return message
+}
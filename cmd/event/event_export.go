@@ -0,0 +1,82 @@
+package event
+
+import (
+	"github.com/alpacanetworks/alpacon-cli/api/event"
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+var eventExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export command events as NDJSON, CSV or syslog lines",
+	Long: `
+	Export command events, oldest first, for ingestion by a SIEM or log pipeline.
+	Events are written as NDJSON, CSV or RFC 5424 syslog lines to standard output or to the file given with '--output'.
+	With '--checkpoint', the time of the last exported event is saved to a file after the output has been written,
+	and the next run only exports newer events, so a cron job can pick up where it left off.
+	The checkpoint is only advanced once the events are on disk, so an interrupted run exports them again.
+	Export stops before the oldest command that is still running, so every event is exported with its outcome.
+	`,
+	Example: `
+	# Export last week's failed commands as CSV
+	alpacon event export --since 7d --status failed --format csv -o failed.csv
+
+	# Append new events to a syslog file, from cron
+	alpacon event export --format syslog --checkpoint /var/lib/alpacon/events.checkpoint --append -o /var/log/alpacon/events.log
+	`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		serverName, _ := cmd.Flags().GetString("server")
+		userName, _ := cmd.Flags().GetString("user")
+		format, _ := cmd.Flags().GetString("format")
+		output, _ := cmd.Flags().GetString("output")
+		appendTo, _ := cmd.Flags().GetBool("append")
+		checkpointPath, _ := cmd.Flags().GetString("checkpoint")
+		filter := parseFilter(cmd, serverName, userName)
+
+		if !utils.ValidExportFormat(format) {
+			utils.CliError("Invalid format '%s'. Choose one of %s.", format, strings.Join(utils.ExportFormats, ", "))
+		}
+
+		var checkpoint event.ExportCheckpoint
+		if checkpointPath != "" {
+			if _, err := utils.ReadCheckpoint(checkpointPath, &checkpoint); err != nil {
+				utils.CliError("Failed to read the checkpoint: %s.", err)
+			}
+		}
+
+		alpaconClient, err := client.NewAlpaconAPIClient()
+		if err != nil {
+			utils.CliError("Connection to Alpacon API failed: %s. Consider re-logging.", err)
+		}
+
+		events, err := event.ListEventsAfter(alpaconClient, filter, checkpoint)
+		if err != nil {
+			utils.CliError("Failed to get events: %s.", err)
+		}
+
+		if err = utils.ExportRecords(output, appendTo, format, events); err != nil {
+			utils.CliError("Failed to write events: %s.", err)
+		}
+
+		if checkpointPath != "" {
+			checkpoint.Advance(events)
+			if err = utils.WriteCheckpoint(checkpointPath, checkpoint); err != nil {
+				utils.CliError("Failed to save the checkpoint: %s.", err)
+			}
+		}
+		utils.CliInfo("Exported %d event(s).", len(events))
+	},
+}
+
+func init() {
+	eventExportCmd.Flags().StringP("server", "s", "", "Only export events of this server")
+	eventExportCmd.Flags().StringP("user", "u", "", "Only export events requested by this user")
+	eventExportCmd.Flags().String("format", "ndjson", "Output format: "+strings.Join(utils.ExportFormats, ", "))
+	eventExportCmd.Flags().StringP("output", "o", "", "Write to this file instead of standard output")
+	eventExportCmd.Flags().Bool("append", false, "Append to the output file instead of replacing it")
+	eventExportCmd.Flags().String("checkpoint", "", "Only export events requested since the last run, tracked in this file")
+	addFilterFlags(eventExportCmd)
+}
@@ -0,0 +1,180 @@
+package event
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github.com/alpacanetworks/alpacon-cli/api/event"
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"github.com/spf13/cobra"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const defaultReportSince = "30d"
+
+var eventReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Summarize command events for audits",
+	Long: `
+	The report command pages through all command events in a time range and aggregates them by user,
+	server or command, with the number of events, the number and rate of failures and the mean elapsed time.
+	Give the users who are expected to run commands with '--allow-users' to list everyone else per row.
+	The report covers the last 30 days unless '--since' is given.
+	The report is printed as a table, or as CSV or Markdown with '--format'.
+	`,
+	Example: `
+	# Which commands ran as root in the last 30 days, and by whom outside the on-call team
+	alpacon event report --since 30d --run-as root --group-by command --allow-users alice,bob
+
+	# Failure rates per server last week, as Markdown
+	alpacon event report --since 7d --group-by server --format markdown
+
+	# Per-user activity on one server, as CSV
+	alpacon event report --since 2024-03-01 --until 2024-04-01 -s myserver --format csv > report.csv
+	`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		serverName, _ := cmd.Flags().GetString("server")
+		userName, _ := cmd.Flags().GetString("user")
+		runAs, _ := cmd.Flags().GetString("run-as")
+		groupBy, _ := cmd.Flags().GetString("group-by")
+		format, _ := cmd.Flags().GetString("format")
+		allowUsers, _ := cmd.Flags().GetStringSlice("allow-users")
+
+		if !cmd.Flags().Changed("since") {
+			_ = cmd.Flags().Set("since", defaultReportSince)
+		}
+		filter := parseFilter(cmd, serverName, userName)
+		filter.RunAs = runAs
+
+		if format != "table" && format != "csv" && format != "markdown" {
+			utils.CliError("Invalid format '%s'. Choose one of table, csv or markdown.", format)
+		}
+		if !event.ValidReportGroup(groupBy) {
+			utils.CliError("Invalid group '%s'. Choose one of %s.", groupBy, strings.Join(event.ReportGroups, ", "))
+		}
+
+		alpaconClient, err := client.NewAlpaconAPIClient()
+		if err != nil {
+			utils.CliError("Connection to Alpacon API failed: %s. Consider re-logging.", err)
+		}
+
+		events, err := event.ListEvents(alpaconClient, filter, 0)
+		if err != nil {
+			utils.CliError("Failed to get events: %s.", err)
+		}
+
+		rows, err := event.BuildReport(events, groupBy, allowUsers)
+		if err != nil {
+			utils.CliError("Failed to build the report: %s.", err)
+		}
+
+		switch format {
+		case "csv":
+			err = writeReportCSV(rows, groupBy)
+			if err != nil {
+				utils.CliError("Failed to write the report: %s.", err)
+			}
+		case "markdown":
+			writeReportMarkdown(rows, groupBy)
+		default:
+			printReportTable(rows, groupBy)
+			utils.CliInfo("%d event(s) in %d group(s).", len(events), len(rows))
+		}
+	},
+}
+
+func init() {
+	eventReportCmd.Flags().StringP("server", "s", "", "Only include events of this server")
+	eventReportCmd.Flags().StringP("user", "u", "", "Only include events requested by this user")
+	eventReportCmd.Flags().String("run-as", "", "Only include commands run as this user on the server (e.g. root, which includes commands naming no user)")
+	eventReportCmd.Flags().String("group-by", "user", "Group events by user, server or command")
+	eventReportCmd.Flags().String("format", "table", "Output format: table, csv or markdown")
+	eventReportCmd.Flags().StringSlice("allow-users", nil, "Users expected to run commands; others are listed per row")
+	addFilterFlags(eventReportCmd)
+}
+
+// reportGroupTitle names the first report column after the grouping, e.g. "Server".
+func reportGroupTitle(groupBy string) string {
+	return strings.ToUpper(groupBy[:1]) + groupBy[1:]
+}
+
+func printReportTable(rows []event.ReportRow, groupBy string) {
+	headers := []string{reportGroupTitle(groupBy), "Count", "Failed", "FailureRate", "MeanElapsed", "UnlistedUsers"}
+	var cells [][]string
+	for _, attributes := range event.ReportAttributesList(rows) {
+		cells = append(cells, []string{
+			attributes.Key,
+			strconv.Itoa(attributes.Count),
+			strconv.Itoa(attributes.Failed),
+			attributes.FailureRate,
+			attributes.MeanElapsed,
+			attributes.UnlistedUsers,
+		})
+	}
+	utils.PrintRows(headers, cells)
+}
+
+func writeReportCSV(rows []event.ReportRow, groupBy string) error {
+	w := csv.NewWriter(os.Stdout)
+	err := w.Write([]string{groupBy, "count", "failed", "failure_rate", "mean_elapsed_seconds", "unlisted_users"})
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		err = w.Write([]string{
+			row.Key,
+			strconv.Itoa(row.Count),
+			strconv.Itoa(row.Failed),
+			strconv.FormatFloat(row.FailureRate(), 'f', 1, 64),
+			strconv.FormatFloat(row.MeanElapsed, 'f', 3, 64),
+			strings.Join(row.UnlistedUsers, ";"),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func writeReportMarkdown(rows []event.ReportRow, groupBy string) {
+	fmt.Printf("| %s | Count | Failed | Failure rate | Mean elapsed | Unlisted users |\n", reportGroupTitle(groupBy))
+	fmt.Println("|---|---:|---:|---:|---:|---|")
+
+	escape := strings.NewReplacer("|", `\|`, "\n", " ")
+	for _, row := range rows {
+		unlisted := strings.Join(row.UnlistedUsers, ", ")
+		if unlisted != "" {
+			// Make users outside the allowlist stand out in rendered reports.
+			unlisted = "**" + escape.Replace(unlisted) + "**"
+		}
+		fmt.Printf("| %s | %d | %d | %.1f%% | %.2fs | %s |\n",
+			markdownCode(escape.Replace(row.Key)), row.Count, row.Failed, row.FailureRate(), row.MeanElapsed, unlisted)
+	}
+}
+
+// markdownCode wraps text in a code span delimited by more backticks than any run inside it,
+// padding it with spaces when it starts or ends with a backtick, as CommonMark requires.
+func markdownCode(text string) string {
+	longest, run := 0, 0
+	for _, r := range text {
+		if r == '`' {
+			run++
+			if run > longest {
+				longest = run
+			}
+		} else {
+			run = 0
+		}
+	}
+
+	fence := strings.Repeat("`", longest+1)
+	if strings.HasPrefix(text, "`") || strings.HasSuffix(text, "`") {
+		text = " " + text + " "
+	}
+	return fence + text + fence
+}
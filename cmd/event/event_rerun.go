@@ -0,0 +1,63 @@
+package event
+
+import (
+	"github.com/alpacanetworks/alpacon-cli/api/event"
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"github.com/spf13/cobra"
+)
+
+var eventRerunCmd = &cobra.Command{
+	Use:   "rerun [EVENT ID]",
+	Short: "Run the command of an event again",
+	Long: `
+	The rerun command submits the command of an existing event again, on the same server,
+	in the same shell and as the same user and group, with the same environment.
+	It waits for the new command to finish and shows its details, unless '--detach' is given.
+	You are asked for confirmation unless '--yes' is given.
+	`,
+	Example: `
+	alpacon event rerun 7c8b3a1e-52f4-4f8e-9d1b-0f1e2d3c4b5a
+	alpacon event rerun -y --detach 7c8b3a1e-52f4-4f8e-9d1b-0f1e2d3c4b5a
+	`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		yes, _ := cmd.Flags().GetBool("yes")
+		detach, _ := cmd.Flags().GetBool("detach")
+
+		alpaconClient, err := client.NewAlpaconAPIClient()
+		if err != nil {
+			utils.CliError("Connection to Alpacon API failed: %s. Consider re-logging.", err)
+		}
+
+		original, err := event.GetEventDetail(alpaconClient, args[0])
+		if err != nil {
+			utils.CliError("Failed to retrieve the event details: %s.", err)
+		}
+
+		if !yes && !utils.PromptForBool("Run '"+original.Line+"' on "+original.ServerName+" again?") {
+			utils.CliInfoWithExit("Aborted.")
+		}
+
+		eventID, err := event.RerunEvent(alpaconClient, original)
+		if err != nil {
+			utils.CliError("Failed to rerun the event: %s.", err)
+		}
+		utils.CliInfo("Submitted the command again as event %s.", eventID)
+
+		if detach {
+			return
+		}
+
+		result, err := event.PollCommandExecution(alpaconClient, eventID)
+		if err != nil {
+			utils.CliError("Failed to wait for event %s: %s.", eventID, err)
+		}
+		printEventDetail(result)
+	},
+}
+
+func init() {
+	eventRerunCmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation")
+	eventRerunCmd.Flags().Bool("detach", false, "Return after submitting the command instead of waiting for its result")
+}
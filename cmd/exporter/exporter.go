@@ -0,0 +1,78 @@
+package exporter
+
+import (
+	"fmt"
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"github.com/alpacanetworks/alpacon-cli/pkg/exporter"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"github.com/spf13/cobra"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var ExporterCmd = &cobra.Command{
+	Use:   "exporter",
+	Short: "Expose fleet state as Prometheus metrics",
+	Long: `
+	Run a Prometheus exporter that periodically fetches the server list, certificates and authorities from Alpacon
+	and exposes them as metrics: agent connection, commissioning, load, response delay, boot time, CPU and memory
+	per server, the number of servers per OS, and certificate and authority expiry timestamps.
+	Scrapes are answered from the last refresh. If a refresh fails, the previous metrics are kept
+	and alpacon_exporter_refresh_success is set to 0.
+	`,
+	Example: `
+	alpacon exporter
+	alpacon exporter --listen 127.0.0.1:9477 --interval 30s
+	`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		listen, _ := cmd.Flags().GetString("listen")
+		interval, _ := cmd.Flags().GetDuration("interval")
+		metricsPath, _ := cmd.Flags().GetString("path")
+
+		if interval <= 0 {
+			utils.CliError("The refresh interval must be positive.")
+		}
+		if !strings.HasPrefix(metricsPath, "/") || metricsPath == "/" {
+			utils.CliError("Invalid path '%s'. It must start with / and not be the root.", metricsPath)
+		}
+
+		alpaconClient, err := client.NewAlpaconAPIClient()
+		if err != nil {
+			utils.CliError("Connection to Alpacon API failed: %s. Consider re-logging.", err)
+		}
+
+		e := exporter.NewExporter(alpaconClient)
+		if err = e.Refresh(); err != nil {
+			utils.CliError("Failed to collect metrics: %s.", err)
+		}
+		go e.Run(interval)
+
+		mux := http.NewServeMux()
+		mux.Handle(metricsPath, e)
+		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/" {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = fmt.Fprintf(w, "Alpacon exporter\nMetrics are served at %s\n", metricsPath)
+		})
+
+		httpServer := &http.Server{
+			Addr:              listen,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		utils.CliInfo("Serving metrics on %s%s, refreshed every %s.", listen, metricsPath, interval)
+		if err = httpServer.ListenAndServe(); err != nil {
+			utils.CliError("Failed to serve metrics: %s.", err)
+		}
+	},
+}
+
+func init() {
+	ExporterCmd.Flags().String("listen", ":9477", "Address to serve metrics on")
+	ExporterCmd.Flags().Duration("interval", time.Minute, "How often to refresh the metrics from Alpacon")
+	ExporterCmd.Flags().String("path", "/metrics", "Path to serve metrics at")
+}
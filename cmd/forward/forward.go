@@ -0,0 +1,118 @@
+package forward
+
+import (
+	"github.com/alpacanetworks/alpacon-cli/api/log"
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"github.com/alpacanetworks/alpacon-cli/config"
+	"github.com/alpacanetworks/alpacon-cli/pkg/forward"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"github.com/spf13/cobra"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var ForwardCmd = &cobra.Command{
+	Use:   "forward",
+	Short: "Forward logs and events to a syslog or HTTP sink",
+	Long: `
+	Keep following server logs and command events and push them in batches to a syslog server or an HTTP endpoint,
+	to feed Alpacon into ELK, Loki or any other log pipeline. The command runs until it is stopped.
+	Supported sinks are syslog://host[:port] (UDP), syslog+tcp://host[:port], and http(s):// URLs.
+	HTTP requests carry NDJSON, an Elasticsearch bulk request for URLs ending in /_bulk,
+	or a Loki push request for /loki/api/v1/push.
+	What has been delivered is recorded in a state file after every batch, so a restarted forwarder
+	continues where it stopped and every record is delivered at least once. By default, the state file is
+	named after the sink, the sources and the filters, so each forwarder keeps its own. Without a state file,
+	forwarding starts with new records, or from '--since' if given. Failed deliveries are retried with backoff.
+	`,
+	Example: `
+	alpacon forward --to syslog://127.0.0.1:514
+	alpacon forward --to http://localhost:9200/alpacon/_bulk --source logs --level WARN
+	alpacon forward --to http://localhost:3100/loki/api/v1/push --since 1h --state /var/lib/alpacon/forward.json
+	`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		to, _ := cmd.Flags().GetString("to")
+		sources, _ := cmd.Flags().GetStringSlice("source")
+		statePath, _ := cmd.Flags().GetString("state")
+		interval, _ := cmd.Flags().GetDuration("interval")
+		batchSize, _ := cmd.Flags().GetInt("batch-size")
+		serverName, _ := cmd.Flags().GetString("server")
+		level, _ := cmd.Flags().GetString("level")
+		since, _ := cmd.Flags().GetString("since")
+
+		if to == "" {
+			utils.CliError("A sink is required. Specify it with '--to'.")
+		}
+
+		opts := forward.Options{Interval: interval, BatchSize: batchSize}
+		for _, source := range sources {
+			switch source {
+			case "logs":
+				opts.Logs = true
+			case "events":
+				opts.Events = true
+			default:
+				utils.CliError("Invalid source '%s'. Choose logs, events or both.", source)
+			}
+		}
+		if interval <= 0 || batchSize <= 0 {
+			utils.CliError("The interval and the batch size must be positive.")
+		}
+
+		opts.LogFilter.ServerName = serverName
+		opts.EventFilter.ServerName = serverName
+		var err error
+		if level != "" {
+			if opts.LogFilter.MinLevel, opts.LogFilter.MaxLevel, err = log.ParseLevelRange(level); err != nil {
+				utils.CliError("Invalid --level: %s.", err)
+			}
+		}
+		if since != "" {
+			if opts.LogFilter.Since, err = utils.ParseTimeArg(since, time.Now()); err != nil {
+				utils.CliError("Invalid --since: %s.", err)
+			}
+			opts.EventFilter.Since = opts.LogFilter.Since
+		}
+
+		if statePath == "" {
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				utils.CliError("Failed to get user home directory: %s.", err)
+			}
+			statePath = filepath.Join(homeDir, config.ConfigFileDir, forward.StateFileName(to, opts))
+		}
+		if err = os.MkdirAll(filepath.Dir(statePath), 0700); err != nil {
+			utils.CliError("Failed to create the state directory: %s.", err)
+		}
+		opts.StatePath = statePath
+
+		sink, err := forward.NewSink(to)
+		if err != nil {
+			utils.CliError("Invalid --to: %s.", err)
+		}
+		defer func() { _ = sink.Close() }()
+
+		alpaconClient, err := client.NewAlpaconAPIClient()
+		if err != nil {
+			utils.CliError("Connection to Alpacon API failed: %s. Consider re-logging.", err)
+		}
+
+		utils.CliInfo("Forwarding %v to %s. Delivery state is kept in %s.", sources, to, statePath)
+		if err = forward.Run(alpaconClient, sink, opts); err != nil {
+			utils.CliError("Failed to forward: %s.", err)
+		}
+	},
+}
+
+func init() {
+	ForwardCmd.Flags().String("to", "", "Sink to forward to, e.g. syslog://127.0.0.1:514 or http://localhost:9200/alpacon/_bulk")
+	ForwardCmd.Flags().StringSlice("source", []string{"logs", "events"}, "What to forward: logs, events or both")
+	ForwardCmd.Flags().String("state", "", "File recording what has been delivered (default ~/.alpacon/forward-[HASH].json, derived from the sink, sources and filters)")
+	ForwardCmd.Flags().Duration("interval", 5*time.Second, "How often to poll for new records")
+	ForwardCmd.Flags().Int("batch-size", 500, "Maximum number of records per delivery")
+	ForwardCmd.Flags().StringP("server", "s", "", "Only forward records of this server")
+	ForwardCmd.Flags().String("level", "", "Only forward log entries of this level and above, or matching a condition such as '>=WARN'")
+	ForwardCmd.Flags().String("since", "", "Without a state file, start with records from this time (e.g. 1h, 2006-01-02 or RFC3339)")
+}
@@ -0,0 +1,69 @@
+package fs
+
+import (
+	"errors"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+var FsCmd = &cobra.Command{
+	Use:   "fs",
+	Short: "Inspect and manage files on remote servers",
+	Long: `
+	Inspect and manage files on a server without opening a websh terminal.
+	Paths are given as [SERVER_NAME]:/remote/path, like in the cp command.
+	Commands run as the user and group given by -u and -g, or as the default user of the server.
+	`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := cmd.Help()
+		if err != nil {
+			return err
+		}
+		return errors.New("subcommand error")
+	},
+}
+
+func init() {
+	FsCmd.PersistentFlags().StringP("username", "u", "", "Specify username")
+	FsCmd.PersistentFlags().StringP("groupname", "g", "", "Specify groupname")
+
+	FsCmd.AddCommand(fsListCmd)
+	FsCmd.AddCommand(fsStatCmd)
+	FsCmd.AddCommand(fsRemoveCmd)
+	FsCmd.AddCommand(fsMkdirCmd)
+	FsCmd.AddCommand(fsMoveCmd)
+	FsCmd.AddCommand(fsChmodCmd)
+}
+
+// parseRemotePaths splits [USER_NAME@][SERVER_NAME]:/path arguments, which must all refer to the same server.
+// A username given in the arguments is used when -u is not set.
+func parseRemotePaths(cmd *cobra.Command, args []string) (serverName string, paths []string, username, groupname string) {
+	username, _ = cmd.Flags().GetString("username")
+	groupname, _ = cmd.Flags().GetString("groupname")
+
+	for _, arg := range args {
+		if !strings.Contains(arg, ":") {
+			utils.CliError("Remote paths must be given as [SERVER_NAME]:/remote/path, got '%s'.", arg)
+		}
+		if strings.Contains(arg, "@") {
+			parts := strings.SplitN(arg, "@", 2)
+			if username == "" {
+				username = parts[0]
+			}
+			arg = parts[1]
+		}
+
+		server, remotePath := utils.SplitPath(arg)
+		if serverName != "" && server != serverName {
+			utils.CliError("All paths must be on the same server.")
+		}
+		if remotePath == "" {
+			utils.CliError("Missing remote path for server '%s'.", server)
+		}
+		serverName = server
+		paths = append(paths, remotePath)
+	}
+
+	return serverName, paths, username, groupname
+}
@@ -0,0 +1,44 @@
+package fs
+
+import (
+	"github.com/alpacanetworks/alpacon-cli/api/fs"
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+var fsChmodCmd = &cobra.Command{
+	Use:   "chmod [MODE] [SERVER_NAME]:[PATH]...",
+	Short: "Change the mode of remote files",
+	Long: `
+	Change the permission bits of one or more files on a server. MODE accepts the same octal
+	or symbolic notation as chmod(1).
+	`,
+	Example: `
+	alpacon fs chmod 640 web-1:/etc/app/secret.conf
+	alpacon fs chmod -R u+rwX,go-w -u root web-1:/srv/app
+	`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		recursive, _ := cmd.Flags().GetBool("recursive")
+		mode := args[0]
+		serverName, paths, username, groupname := parseRemotePaths(cmd, args[1:])
+
+		alpaconClient, err := client.NewAlpaconAPIClient()
+		if err != nil {
+			utils.CliError("Connection to Alpacon API failed: %s. Consider re-logging.", err)
+		}
+
+		err = fs.ChangeMode(alpaconClient, serverName, mode, paths, username, groupname, recursive)
+		if err != nil {
+			utils.CliError("Failed to change the mode of %s: %s.", strings.Join(args[1:], ", "), err)
+		}
+
+		utils.CliInfo("Successfully changed the mode of %s to %s.", strings.Join(args[1:], ", "), mode)
+	},
+}
+
+func init() {
+	fsChmodCmd.Flags().BoolP("recursive", "R", false, "Change files and directories recursively")
+}
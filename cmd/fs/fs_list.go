@@ -0,0 +1,43 @@
+package fs
+
+import (
+	"github.com/alpacanetworks/alpacon-cli/api/fs"
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"github.com/spf13/cobra"
+)
+
+var fsListCmd = &cobra.Command{
+	Use:     "ls [SERVER_NAME]:[PATH]",
+	Aliases: []string{"list"},
+	Short:   "List files in a remote directory",
+	Long: `
+	List the entries of a directory on a server with their type, mode, size, owner and modification time.
+	When the path refers to a file, only that file is listed. Hidden entries are shown with '--all'.
+	`,
+	Example: `
+	alpacon fs ls web-1:/var/www
+	alpacon fs ls -a -u root web-1:/root
+	`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		all, _ := cmd.Flags().GetBool("all")
+		serverName, paths, username, groupname := parseRemotePaths(cmd, args)
+
+		alpaconClient, err := client.NewAlpaconAPIClient()
+		if err != nil {
+			utils.CliError("Connection to Alpacon API failed: %s. Consider re-logging.", err)
+		}
+
+		fileList, err := fs.ListFiles(alpaconClient, serverName, paths[0], username, groupname, all)
+		if err != nil {
+			utils.CliError("Failed to list %s: %s.", args[0], err)
+		}
+
+		utils.PrintTable(fileList)
+	},
+}
+
+func init() {
+	fsListCmd.Flags().BoolP("all", "a", false, "Include entries whose names begin with a dot")
+}
@@ -0,0 +1,43 @@
+package fs
+
+import (
+	"github.com/alpacanetworks/alpacon-cli/api/fs"
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+var fsMkdirCmd = &cobra.Command{
+	Use:   "mkdir [SERVER_NAME]:[PATH]...",
+	Short: "Create remote directories",
+	Long: `
+	Create one or more directories on a server. Use '--parents' to create missing parent directories
+	and to succeed when the directory already exists.
+	`,
+	Example: `
+	alpacon fs mkdir web-1:/srv/app/releases
+	alpacon fs mkdir -p -u www-data web-1:/srv/app/releases/2024-01-01
+	`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		parents, _ := cmd.Flags().GetBool("parents")
+		serverName, paths, username, groupname := parseRemotePaths(cmd, args)
+
+		alpaconClient, err := client.NewAlpaconAPIClient()
+		if err != nil {
+			utils.CliError("Connection to Alpacon API failed: %s. Consider re-logging.", err)
+		}
+
+		err = fs.MakeDirectory(alpaconClient, serverName, paths, username, groupname, parents)
+		if err != nil {
+			utils.CliError("Failed to create %s: %s.", strings.Join(args, ", "), err)
+		}
+
+		utils.CliInfo("Successfully created %s.", strings.Join(args, ", "))
+	},
+}
+
+func init() {
+	fsMkdirCmd.Flags().BoolP("parents", "p", false, "Create parent directories as needed")
+}
@@ -0,0 +1,37 @@
+package fs
+
+import (
+	"github.com/alpacanetworks/alpacon-cli/api/fs"
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"github.com/spf13/cobra"
+)
+
+var fsMoveCmd = &cobra.Command{
+	Use:     "mv [SERVER_NAME]:[SOURCE] [SERVER_NAME]:[DESTINATION]",
+	Aliases: []string{"move", "rename"},
+	Short:   "Move or rename a remote file",
+	Long: `
+	Move or rename a file or directory on a server. Both paths must be on the same server.
+	To copy files between servers, use the cp command instead.
+	`,
+	Example: `
+	alpacon fs mv web-1:/srv/app/current web-1:/srv/app/previous
+	`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		serverName, paths, username, groupname := parseRemotePaths(cmd, args)
+
+		alpaconClient, err := client.NewAlpaconAPIClient()
+		if err != nil {
+			utils.CliError("Connection to Alpacon API failed: %s. Consider re-logging.", err)
+		}
+
+		err = fs.MoveFile(alpaconClient, serverName, paths[0], paths[1], username, groupname)
+		if err != nil {
+			utils.CliError("Failed to move %s to %s: %s.", args[0], args[1], err)
+		}
+
+		utils.CliInfo("Successfully moved %s to %s.", args[0], args[1])
+	},
+}
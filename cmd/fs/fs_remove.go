@@ -0,0 +1,50 @@
+package fs
+
+import (
+	"github.com/alpacanetworks/alpacon-cli/api/fs"
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+var fsRemoveCmd = &cobra.Command{
+	Use:     "rm [SERVER_NAME]:[PATH]...",
+	Aliases: []string{"delete"},
+	Short:   "Remove remote files or directories",
+	Long: `
+	Remove one or more files on a server. Directories are only removed with '--recursive'.
+	You are asked for confirmation unless '--yes' is given.
+	`,
+	Example: `
+	alpacon fs rm web-1:/tmp/upload.tar.gz
+	alpacon fs rm -r -y web-1:/srv/app/cache
+	`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		recursive, _ := cmd.Flags().GetBool("recursive")
+		yes, _ := cmd.Flags().GetBool("yes")
+		serverName, paths, username, groupname := parseRemotePaths(cmd, args)
+
+		if !yes && !utils.PromptForBool("Remove "+strings.Join(args, ", ")+"?") {
+			utils.CliInfoWithExit("Aborted.")
+		}
+
+		alpaconClient, err := client.NewAlpaconAPIClient()
+		if err != nil {
+			utils.CliError("Connection to Alpacon API failed: %s. Consider re-logging.", err)
+		}
+
+		err = fs.RemoveFile(alpaconClient, serverName, paths, username, groupname, recursive)
+		if err != nil {
+			utils.CliError("Failed to remove %s: %s.", strings.Join(args, ", "), err)
+		}
+
+		utils.CliInfo("Successfully removed %s.", strings.Join(args, ", "))
+	},
+}
+
+func init() {
+	fsRemoveCmd.Flags().BoolP("recursive", "r", false, "Remove directories and their contents recursively")
+	fsRemoveCmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation")
+}
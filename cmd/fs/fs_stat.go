@@ -0,0 +1,35 @@
+package fs
+
+import (
+	"github.com/alpacanetworks/alpacon-cli/api/fs"
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"github.com/spf13/cobra"
+)
+
+var fsStatCmd = &cobra.Command{
+	Use:   "stat [SERVER_NAME]:[PATH]",
+	Short: "Display the status of a remote file",
+	Long: `
+	Display the type, mode, size, owner, group, modification time and link target of a file or directory on a server.
+	`,
+	Example: `
+	alpacon fs stat web-1:/etc/nginx/nginx.conf
+	`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		serverName, paths, username, groupname := parseRemotePaths(cmd, args)
+
+		alpaconClient, err := client.NewAlpaconAPIClient()
+		if err != nil {
+			utils.CliError("Connection to Alpacon API failed: %s. Consider re-logging.", err)
+		}
+
+		fileDetail, err := fs.GetFileDetail(alpaconClient, serverName, paths[0], username, groupname)
+		if err != nil {
+			utils.CliError("Failed to stat %s: %s.", args[0], err)
+		}
+
+		utils.PrintTable([]fs.FileDetails{fileDetail})
+	},
+}
@@ -6,14 +6,14 @@ import (
 	"github.com/alpacanetworks/alpacon-cli/client"
 	"github.com/alpacanetworks/alpacon-cli/utils"
 	"github.com/spf13/cobra"
+	"os"
 	"strings"
 )
 
 var CpCmd = &cobra.Command{
 	Use:   "cp [SOURCE...] [DESTINATION]",
 	Short: "Copy files between local and remote locations",
-	Long: `The cp command allows you to copy files between your local machine and a remote server.
-	Copy files between your local machine and a remote server using the cp command.
+	Long: `The cp command allows you to copy files between your local machine and a remote server, or between servers.
 	This command supports uploading, downloading, and specifying authentication details
 	such as username and groupname.
 	
@@ -26,7 +26,31 @@ var CpCmd = &cobra.Command{
 	  alpacon cp -r [SERVER_NAME]:/remote/path/directory /local/path/
 
 	- To download files from a remote server to a local destination:
-	  alpacon cp [SERVER_NAME]:/remote/path1 /remote/path2 /local/destination/path
+	  alpacon cp "[SERVER_NAME]:/remote/path1 /remote/path2" /local/destination/path
+
+	- To download files matching wildcards, which are expanded on the server:
+	  alpacon cp '[SERVER_NAME]:/var/log/*.log' /local/destination/path
+
+	  Remote paths are split like in a shell. Quote or escape spaces and wildcards to keep them literal:
+	  alpacon cp '[SERVER_NAME]:"/remote/my dir/file.txt"' /local/destination/path
+	  alpacon cp '[SERVER_NAME]:/remote/report\ 2024.csv' /local/destination/path
+
+	- To copy files or directories from one server to another:
+	  alpacon cp [SERVER_NAME]:/remote/path/file.sql.gz [OTHER_SERVER_NAME]:/remote/path/
+	  alpacon cp -r [SERVER_NAME]:/remote/path/directory [OTHER_SERVER_NAME]:/remote/path/
+
+	  The data is streamed through this machine without being written to local disk.
+	  The username and groupname apply to both servers.
+
+	- To stream standard input to a remote file, or a remote file to standard output:
+	  pg_dump mydb | alpacon cp - [SERVER_NAME]:/remote/path/mydb.sql
+	  alpacon cp [SERVER_NAME]:/remote/path/app.log - | grep ERROR
+
+	  Nothing is written to local disk. Input redirected from a file is uploaded like that file.
+	  Piped input is buffered in memory up to 64 MiB and larger input is sent as a multipart upload,
+	  so piping needs a server that supports multipart uploads and is refused before any input is read
+	  otherwise. Redirect the input from a file in that case:
+	  alpacon cp - [SERVER_NAME]:/remote/path/mydb.sql < mydb.sql
 
 	- To specify username:
 	  alpacon cp /local/path/file.txt [USER_NAME]@[SERVER_NAME]:/remote/path/
@@ -34,11 +58,38 @@ var CpCmd = &cobra.Command{
 
 	- To specify groupname:
 	  alpacon cp -g [GROUP_NAME] /local/path/file.txt [SERVER_NAME]:/remote/path/
+
+	- To control progress output (auto, bar, plain, none):
+	  alpacon cp --progress=plain /local/path/file.txt [SERVER_NAME]:/remote/path/
+	  alpacon cp -q /local/path/file.txt [SERVER_NAME]:/remote/path/
+
+	- To copy many files concurrently (results are still reported in input order):
+	  alpacon cp --parallel 8 /local/path/*.conf [SERVER_NAME]:/remote/path/
+
+	- To verify the copy by comparing SHA-256 checksums of both sides:
+	  alpacon cp --verify /local/path/file.txt [SERVER_NAME]:/remote/path/
+	  alpacon cp --verify -r [SERVER_NAME]:/remote/path/directory /local/path/
+
+	- To continue an interrupted transfer:
+	  alpacon cp --resume /local/path/image.qcow2 [SERVER_NAME]:/remote/path/
+	  alpacon cp --resume [SERVER_NAME]:/remote/path/image.qcow2 /local/path/
+
+	Dropped connections are retried automatically. If a transfer still fails, its state is kept
+	so that '--resume' can continue it later: uploads are recorded under '~/.alpacon/transfers',
+	downloads in a '.alpacon-transfers.json' file in the destination directory.
+
+	Progress bars are shown when stderr is a terminal. Otherwise, progress is reported as
+	key=value lines suitable for CI logs. Use -q (or --progress=none) to disable it.
 	`,
 	Run: func(cmd *cobra.Command, args []string) {
 		username, _ := cmd.Flags().GetString("username")
 		groupname, _ := cmd.Flags().GetString("groupname")
 		recursive, _ := cmd.Flags().GetBool("recursive")
+		quiet, _ := cmd.Flags().GetBool("quiet")
+		progressMode, _ := cmd.Flags().GetString("progress")
+		resume, _ := cmd.Flags().GetBool("resume")
+		parallel, _ := cmd.Flags().GetInt("parallel")
+		verify, _ := cmd.Flags().GetBool("verify")
 
 		if len(args) < 2 {
 			utils.CliError("You must specify at least two arguments.")
@@ -56,6 +107,19 @@ var CpCmd = &cobra.Command{
 			}
 		}
 
+		if quiet {
+			progressMode = utils.ProgressNone
+		}
+		if !utils.ValidProgressMode(progressMode) {
+			utils.CliError("Invalid progress mode '%s'. Choose one of auto, bar, plain or none.", progressMode)
+			return
+		}
+
+		if parallel < 1 {
+			utils.CliError("The number of parallel transfers must be at least 1.")
+			return
+		}
+
 		sources := args[:len(args)-1]
 		dest := args[len(args)-1]
 
@@ -65,10 +129,41 @@ var CpCmd = &cobra.Command{
 			return
 		}
 
-		if isLocalPaths(sources) && isRemotePath(dest) {
-			uploadObject(alpaconClient, sources, dest, username, groupname, recursive)
+		opts := ftp.TransferOptions{
+			Username:  username,
+			Groupname: groupname,
+			Recursive: recursive,
+			Resume:    resume,
+			Parallel:  parallel,
+			Verify:    verify,
+		}
+
+		if (len(sources) == 1 && sources[0] == "-") || dest == "-" {
+			if recursive || resume {
+				utils.CliError("Standard input and output ('-') cannot be combined with --recursive or --resume.")
+				return
+			}
+		}
+
+		if len(sources) == 1 && sources[0] == "-" && isRemotePath(dest) {
+			opts.Progress = utils.NewProgress(progressMode)
+			uploadStream(alpaconClient, dest, opts)
+		} else if len(sources) == 1 && isRemotePath(sources[0]) && dest == "-" {
+			opts.Progress = utils.NewProgress(progressMode)
+			downloadStream(alpaconClient, sources[0], opts)
+		} else if isLocalPaths(sources) && isRemotePath(dest) {
+			opts.Progress = utils.NewProgress(progressMode)
+			uploadObject(alpaconClient, sources, dest, opts)
 		} else if isRemotePath(sources[0]) && isLocalPath(dest) {
-			downloadObject(alpaconClient, sources[0], dest, username, groupname, recursive)
+			opts.Progress = utils.NewProgress(progressMode)
+			downloadObject(alpaconClient, sources[0], dest, opts)
+		} else if isRemotePaths(sources) && isRemotePath(dest) {
+			if resume {
+				utils.CliError("Copies between servers do not keep local state and cannot be resumed.")
+				return
+			}
+			opts.Progress = utils.NewProgress(progressMode)
+			copyObject(alpaconClient, sources, dest, opts)
 		} else {
 			utils.CliError("Invalid combination of source and destination paths.")
 		}
@@ -81,6 +176,11 @@ func init() {
 	CpCmd.Flags().BoolP("recursive", "r", false, "Recursively copy directories")
 	CpCmd.Flags().StringVarP(&username, "username", "u", "", "Specify username")
 	CpCmd.Flags().StringVarP(&groupname, "groupname", "g", "", "Specify groupname")
+	CpCmd.Flags().Int("parallel", 1, "Number of files to transfer concurrently")
+	CpCmd.Flags().Bool("verify", false, "Compare SHA-256 checksums of the local and remote copies after the transfer")
+	CpCmd.Flags().Bool("resume", false, "Resume interrupted transfers recorded by a previous run")
+	CpCmd.Flags().BoolP("quiet", "q", false, "Suppress progress output and the transfer summary")
+	CpCmd.Flags().String("progress", utils.ProgressAuto, "Progress output: auto, bar, plain or none")
 }
 
 // isRemotePath determines if the given path is a remote server path.
@@ -102,29 +202,72 @@ func isLocalPaths(paths []string) bool {
 	return true
 }
 
-func uploadObject(client *client.AlpaconClient, src []string, dest, username, groupname string, recursive bool) {
+func isRemotePaths(paths []string) bool {
+	for _, path := range paths {
+		if isLocalPath(path) {
+			return false
+		}
+	}
+	return true
+}
+
+func uploadObject(client *client.AlpaconClient, src []string, dest string, opts ftp.TransferOptions) {
 	var result []string
 	var err error
 
-	if recursive {
-		result, err = ftp.UploadFolder(client, src, dest, username, groupname)
+	if opts.Recursive {
+		result, err = ftp.UploadFolder(client, src, dest, opts)
 	} else {
-		result, err = ftp.UploadFile(client, src, dest, username, groupname)
+		result, err = ftp.UploadFile(client, src, dest, opts)
 	}
+	opts.Progress.Stop()
 	if err != nil {
 		utils.CliError("Failed to upload the file to server: %s.", err)
 	}
 	wrappedSrc := fmt.Sprintf("[%s]", strings.Join(src, ", "))
 	utils.CliInfo("Upload request for %s to %s successful.", wrappedSrc, dest)
+	opts.Progress.Summary()
 	fmt.Printf("Result: %s.\n", result)
 }
 
-func downloadObject(client *client.AlpaconClient, src, dest, username, groupname string, recursive bool) {
-	var err error
-	err = ftp.DownloadFile(client, src, dest, username, groupname, recursive)
-
+func downloadObject(client *client.AlpaconClient, src, dest string, opts ftp.TransferOptions) {
+	err := ftp.DownloadFile(client, src, dest, opts)
+	opts.Progress.Stop()
 	if err != nil {
 		utils.CliError("Failed to download the file from server: %s.", err)
 	}
 	utils.CliInfo("Download request for %s to server %s successful.", src, dest)
+	opts.Progress.Summary()
+}
+
+func copyObject(client *client.AlpaconClient, src []string, dest string, opts ftp.TransferOptions) {
+	result, err := ftp.CopyRemote(client, src, dest, opts)
+	opts.Progress.Stop()
+	if err != nil {
+		utils.CliError("Failed to copy the file between servers: %s.", err)
+	}
+	wrappedSrc := fmt.Sprintf("[%s]", strings.Join(src, ", "))
+	utils.CliInfo("Copy request for %s to %s successful.", wrappedSrc, dest)
+	opts.Progress.Summary()
+	fmt.Printf("Result: %s.\n", result)
+}
+
+func uploadStream(client *client.AlpaconClient, dest string, opts ftp.TransferOptions) {
+	result, err := ftp.UploadStream(client, os.Stdin, dest, opts)
+	opts.Progress.Stop()
+	if err != nil {
+		utils.CliError("Failed to upload standard input to server: %s.", err)
+	}
+	utils.CliInfo("Upload request for standard input to %s successful.", dest)
+	opts.Progress.Summary()
+	fmt.Printf("Result: %s.\n", result)
+}
+
+func downloadStream(client *client.AlpaconClient, src string, opts ftp.TransferOptions) {
+	err := ftp.DownloadStream(client, src, os.Stdout, opts)
+	opts.Progress.Stop()
+	if err != nil {
+		utils.CliError("Failed to download the file from server: %s.", err)
+	}
+	opts.Progress.Summary()
 }
@@ -0,0 +1,171 @@
+package ftp
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/alpacanetworks/alpacon-cli/api/fs"
+	"github.com/alpacanetworks/alpacon-cli/api/ftp"
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"github.com/spf13/cobra"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+var EditCmd = &cobra.Command{
+	Use:   "edit [SERVER_NAME]:[PATH]",
+	Short: "Edit a remote file in your local editor",
+	Long: `
+	The edit command downloads a remote file, opens it in the editor set by $VISUAL or $EDITOR (vi by default),
+	and shows the changes when the editor exits. After confirmation, the file is uploaded back
+	with its original owner, group and mode.
+	If the remote file was changed by someone else in the meantime, nothing is overwritten and
+	the edited copy is kept locally.
+	`,
+	Example: `
+	alpacon edit web-1:/etc/nginx/nginx.conf
+	alpacon edit -u root web-1:/etc/hosts
+	EDITOR=nano alpacon edit web-1:/srv/app/.env
+	`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		username, _ := cmd.Flags().GetString("username")
+		groupname, _ := cmd.Flags().GetString("groupname")
+		yes, _ := cmd.Flags().GetBool("yes")
+
+		arg := args[0]
+		if strings.Contains(arg, "@") && strings.Contains(arg, ":") {
+			parts := strings.SplitN(arg, "@", 2)
+			if username == "" {
+				username = parts[0]
+			}
+			arg = parts[1]
+		}
+		if !isRemotePath(arg) {
+			utils.CliError("The file must be given as [SERVER_NAME]:/remote/path.")
+		}
+		serverName, remotePath := utils.SplitPath(arg)
+
+		alpaconClient, err := client.NewAlpaconAPIClient()
+		if err != nil {
+			utils.CliError("Connection to Alpacon API failed: %s. Consider re-logging.", err)
+		}
+
+		tmpDir, err := os.MkdirTemp("", "alpacon-edit-*")
+		if err != nil {
+			utils.CliError("Failed to create a temporary directory: %s.", err)
+		}
+
+		keep, err := editRemoteFile(alpaconClient, serverName, remotePath, username, groupname, tmpDir, yes)
+		if !keep {
+			_ = os.RemoveAll(tmpDir)
+		}
+		if err != nil {
+			utils.CliError("Failed to edit %s: %s.", arg, err)
+		}
+	},
+}
+
+func init() {
+	EditCmd.Flags().StringP("username", "u", "", "Specify username used to read the file")
+	EditCmd.Flags().StringP("groupname", "g", "", "Specify groupname used to read the file")
+	EditCmd.Flags().BoolP("yes", "y", false, "Upload the changes without asking for confirmation")
+}
+
+// editRemoteFile runs the download, edit and upload cycle for remotePath using tmpDir as the working directory.
+// It reports whether tmpDir holds edits that could not be uploaded and must be kept.
+func editRemoteFile(ac *client.AlpaconClient, serverName, remotePath, username, groupname, tmpDir string, yes bool) (bool, error) {
+	detail, err := fs.GetFileDetail(ac, serverName, remotePath, username, groupname)
+	if err != nil {
+		return false, err
+	}
+	if detail.Type != "file" {
+		return false, fmt.Errorf("%s is a %s, not a regular file", remotePath, detail.Type)
+	}
+	mode, err := fs.OctalMode(detail.Mode)
+	if err != nil {
+		return false, err
+	}
+
+	opts := ftp.TransferOptions{
+		Username:  username,
+		Groupname: groupname,
+		Progress:  utils.NewProgress(utils.ProgressNone),
+	}
+	err = ftp.DownloadFile(ac, serverName+":"+utils.ShellQuote(remotePath), tmpDir, opts)
+	if err != nil {
+		return false, err
+	}
+
+	localPath := filepath.Join(tmpDir, path.Base(remotePath))
+	original, err := os.ReadFile(localPath)
+	if err != nil {
+		return false, err
+	}
+	originalSum := sha256.Sum256(original)
+
+	if err = utils.EditFile(localPath); err != nil {
+		return false, fmt.Errorf("editor failed: %v", err)
+	}
+	edited, err := os.ReadFile(localPath)
+	if err != nil {
+		return false, err
+	}
+
+	if bytes.Equal(original, edited) {
+		utils.CliInfo("No changes made to %s.", remotePath)
+		return false, nil
+	}
+
+	printDiff(utils.UnifiedDiff(original, edited, serverName+":"+remotePath, "edited"))
+	if !yes && !utils.PromptForBool(fmt.Sprintf("Upload the changes to %s:%s?", serverName, remotePath)) {
+		utils.CliInfo("Aborted. The edited copy is kept at %s.", localPath)
+		return true, nil
+	}
+
+	currentSum, err := ftp.RemoteChecksum(ac, serverName, remotePath, username, groupname)
+	if err != nil {
+		return true, fmt.Errorf("%v (the edited copy is kept at %s)", err, localPath)
+	}
+	if currentSum != hex.EncodeToString(originalSum[:]) {
+		return true, fmt.Errorf("%s was changed on the server while it was being edited; the edited copy is kept at %s", remotePath, localPath)
+	}
+
+	// Upload as the owner of the file so that its ownership does not change.
+	opts.Username = detail.Owner
+	opts.Groupname = detail.Group
+	_, err = ftp.UploadFile(ac, []string{localPath}, serverName+":"+path.Dir(remotePath), opts)
+	if err != nil {
+		return true, fmt.Errorf("%v (the edited copy is kept at %s)", err, localPath)
+	}
+
+	err = fs.ChangeMode(ac, serverName, mode, []string{remotePath}, detail.Owner, detail.Group, false)
+	if err != nil {
+		return false, fmt.Errorf("the file was saved, but its mode could not be restored to %s: %v", mode, err)
+	}
+
+	utils.CliInfo("Saved %s:%s.", serverName, remotePath)
+	return false, nil
+}
+
+// printDiff prints a unified diff with removed lines in red and added lines in green.
+func printDiff(diff string) {
+	for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "---"), strings.HasPrefix(line, "+++"):
+			fmt.Println(line)
+		case strings.HasPrefix(line, "-"):
+			fmt.Println(utils.Red(line))
+		case strings.HasPrefix(line, "+"):
+			fmt.Println(utils.Green(line))
+		case strings.HasPrefix(line, "@@"):
+			fmt.Println(utils.Blue(line))
+		default:
+			fmt.Println(line)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package ftp
+
+import (
+	"github.com/alpacanetworks/alpacon-cli/api/ftp"
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"github.com/spf13/cobra"
+)
+
+var SyncCmd = &cobra.Command{
+	Use:   "sync [SOURCE] [DESTINATION]",
+	Short: "Synchronize a directory tree between local and remote locations",
+	Long: `
+	The sync command makes the destination directory mirror the source directory, transferring only
+	files that are new or changed. Files are compared by size and modification time, or by SHA-256
+	checksum with '--checksum'. The remote side is inspected with a command run on the server.
+	Either the source or the destination must be a remote path ([SERVER_NAME]:/remote/path).
+	`,
+	Example: `
+	# Upload changes of ./dist to /srv/app on web-1
+	alpacon sync ./dist web-1:/srv/app
+
+	# Download changes of /etc/nginx on web-1 to ./nginx
+	alpacon sync web-1:/etc/nginx ./nginx
+
+	# Preview the transfer, removing remote files that no longer exist locally
+	alpacon sync --delete --dry-run ./dist web-1:/srv/app
+
+	# Skip files and directories matching patterns
+	alpacon sync --exclude '*.map' --exclude node_modules ./dist web-1:/srv/app
+
+	# Run as a specific user and group
+	alpacon sync -u www-data -g www-data ./dist web-1:/srv/app
+	`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		username, _ := cmd.Flags().GetString("username")
+		groupname, _ := cmd.Flags().GetString("groupname")
+		deleteExtraneous, _ := cmd.Flags().GetBool("delete")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		checksum, _ := cmd.Flags().GetBool("checksum")
+		exclude, _ := cmd.Flags().GetStringArray("exclude")
+		parallel, _ := cmd.Flags().GetInt("parallel")
+		quiet, _ := cmd.Flags().GetBool("quiet")
+		progressMode, _ := cmd.Flags().GetString("progress")
+
+		if quiet {
+			progressMode = utils.ProgressNone
+		}
+		if !utils.ValidProgressMode(progressMode) {
+			utils.CliError("Invalid progress mode '%s'. Choose one of auto, bar, plain or none.", progressMode)
+		}
+		if parallel < 1 {
+			utils.CliError("The number of parallel transfers must be at least 1.")
+		}
+
+		src, dest := args[0], args[1]
+		if isRemotePath(src) == isRemotePath(dest) {
+			utils.CliError("Exactly one of the source and destination must be a remote path.")
+		}
+
+		alpaconClient, err := client.NewAlpaconAPIClient()
+		if err != nil {
+			utils.CliError("Connection to Alpacon API failed: %s. Consider re-logging.", err)
+		}
+
+		opts := ftp.SyncOptions{
+			TransferOptions: ftp.TransferOptions{
+				Username:  username,
+				Groupname: groupname,
+				Parallel:  parallel,
+				Progress:  utils.NewProgress(progressMode),
+			},
+			Delete:   deleteExtraneous,
+			DryRun:   dryRun,
+			Checksum: checksum,
+			Exclude:  exclude,
+		}
+
+		var result []ftp.SyncAttributes
+		if isRemotePath(dest) {
+			result, err = ftp.SyncToRemote(alpaconClient, src, dest, opts)
+		} else {
+			result, err = ftp.SyncFromRemote(alpaconClient, src, dest, opts)
+		}
+		opts.Progress.Stop()
+		if err != nil {
+			utils.CliError("Failed to synchronize %s to %s: %s.", src, dest, err)
+		}
+
+		if len(result) == 0 {
+			utils.CliInfo("%s is already up to date.", dest)
+			return
+		}
+
+		if dryRun {
+			utils.CliInfo("Dry run: the following changes would be made to %s.", dest)
+			utils.PrintTable(result)
+			return
+		}
+
+		utils.PrintTable(result)
+		opts.Progress.Summary()
+		utils.CliInfo("Synchronized %s to %s (%d change(s)).", src, dest, len(result))
+	},
+}
+
+func init() {
+	SyncCmd.Flags().StringP("username", "u", "", "Specify username")
+	SyncCmd.Flags().StringP("groupname", "g", "", "Specify groupname")
+	SyncCmd.Flags().Bool("delete", false, "Delete files in the destination that do not exist in the source")
+	SyncCmd.Flags().BoolP("dry-run", "n", false, "Show what would be transferred without making changes")
+	SyncCmd.Flags().BoolP("checksum", "c", false, "Compare files by SHA-256 checksum instead of size and modification time")
+	SyncCmd.Flags().StringArray("exclude", nil, "Exclude files matching the pattern (can be repeated)")
+	SyncCmd.Flags().Int("parallel", 1, "Number of files to transfer concurrently")
+	SyncCmd.Flags().BoolP("quiet", "q", false, "Suppress progress output and the transfer summary")
+	SyncCmd.Flags().String("progress", utils.ProgressAuto, "Progress output: auto, bar, plain or none")
+}
@@ -1,37 +1,68 @@
 package log
 
 import (
+	"fmt"
 	"github.com/alpacanetworks/alpacon-cli/api/log"
 	"github.com/alpacanetworks/alpacon-cli/client"
 	"github.com/alpacanetworks/alpacon-cli/utils"
 	"github.com/spf13/cobra"
+	"regexp"
+	"time"
 )
 
+const followInterval = 2 * time.Second
+
 var LogCmd = &cobra.Command{
 	Use:     "log [SERVER NAME]",
 	Aliases: []string{"logs"},
 	Short:   "Retrieve and display server logs",
-	Long: `Retrieve and display logs for a specified server. This command allows you 
-	to view logs of different levels and types associated with a server. Use the '--tail' flag 
-	to limit the output to the last N log entries. Suitable for debugging and monitoring 
-	server activities.`,
+	Long: `Retrieve and display logs for a specified server, or for all servers when no server is given.
+	This command allows you to view logs of different levels and types associated with a server.
+	Use the '--tail' flag to limit the output to the last N log entries. Suitable for debugging and monitoring 
+	server activities. Use the '--follow' flag to keep printing new log entries as they arrive,
+	like 'journalctl -f'. It starts with the last '--tail' entries; '--tail 0' shows only new ones.
+	Narrow the logs down with '--level' (e.g. WARN for warnings and above, or '>=ERROR', '<INFO', '=DEBUG'),
+	'--program', '--process', '--thread', '--grep' with a regular expression matched against the message,
+	and '--since' and '--until', which take an age such as 2h or 7d, a date, or an RFC3339 timestamp.
+	The server cannot match '--grep' itself, so without '--since' only the newest 5000 entries are searched.`,
 	Example: `
 	alpacon log [SERVER NAME]
 	alpacon logs [SERVER_NAME]
 	alpacon log [SERVER NAME] --tail=10
 	alpacon logs [SERVER NAME] --tail=10
+	alpacon log [SERVER NAME] -f
+	alpacon log [SERVER NAME] --level '>=WARN' --since 2h
+	alpacon log --level ERROR --program alpamon --grep 'timeout|refused'
+	alpacon log export --format syslog -o logs.txt
 	`,
-	Args: cobra.ExactArgs(1),
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		serverName := args[0]
+		var serverName string
+		if len(args) > 0 {
+			serverName = args[0]
+		}
 		pageSize, _ := cmd.Flags().GetInt("tail")
+		follow, _ := cmd.Flags().GetBool("follow")
+		filter := parseFilter(cmd, serverName)
+
+		if pageSize < 0 {
+			utils.CliError("The number of log entries must not be negative.")
+		}
 
 		alpaconClient, err := client.NewAlpaconAPIClient()
 		if err != nil {
 			utils.CliError("Connection to Alpacon API failed: %s. Consider re-logging.", err)
 		}
 
-		logList, err := log.GetSystemLogList(alpaconClient, serverName, pageSize)
+		if follow {
+			err = log.FollowSystemLogs(alpaconClient, filter, pageSize, followInterval, printLogEntry)
+			if err != nil {
+				utils.CliError("Failed to follow logs: %s.", err)
+			}
+			return
+		}
+
+		logList, err := log.GetSystemLogList(alpaconClient, pageSize, filter)
 		if err != nil {
 			utils.CliError("Failed to get logs: %s.", err)
 		}
@@ -43,5 +74,79 @@ var LogCmd = &cobra.Command{
 func init() {
 	var pageSize int
 
-	LogCmd.Flags().IntVarP(&pageSize, "tail", "t", 25, "Number of log entries to show from the end")
+	LogCmd.Flags().IntVarP(&pageSize, "tail", "t", 25, "Number of log entries to show from the end (0 for all, or only new entries with --follow)")
+	LogCmd.Flags().BoolP("follow", "f", false, "Keep streaming new log entries as they are added")
+	addFilterFlags(LogCmd)
+
+	LogCmd.AddCommand(logExportCmd)
+}
+
+// addFilterFlags adds the level, program, time, text, process and thread filters shared by the log commands.
+func addFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().String("level", "", "Show only entries of this level and above, or matching a condition such as '>=WARN'")
+	cmd.Flags().String("program", "", "Show only entries of this program")
+	cmd.Flags().String("since", "", "Show entries logged after this time (e.g. 2h, 7d, 2006-01-02 or RFC3339)")
+	cmd.Flags().String("until", "", "Show entries logged before this time (e.g. 1h, 2006-01-02 or RFC3339)")
+	cmd.Flags().String("grep", "", "Show only entries whose message matches this regular expression")
+	cmd.Flags().String("process", "", "Show only entries of this process")
+	cmd.Flags().String("thread", "", "Show only entries of this thread")
+}
+
+// parseFilter builds a log filter from the flags added by addFilterFlags and the server name.
+func parseFilter(cmd *cobra.Command, serverName string) log.LogFilter {
+	level, _ := cmd.Flags().GetString("level")
+	program, _ := cmd.Flags().GetString("program")
+	since, _ := cmd.Flags().GetString("since")
+	until, _ := cmd.Flags().GetString("until")
+	grep, _ := cmd.Flags().GetString("grep")
+	process, _ := cmd.Flags().GetString("process")
+	thread, _ := cmd.Flags().GetString("thread")
+
+	filter := log.LogFilter{
+		ServerName: serverName,
+		Program:    program,
+		Process:    process,
+		Thread:     thread,
+	}
+
+	var err error
+	if level != "" {
+		if filter.MinLevel, filter.MaxLevel, err = log.ParseLevelRange(level); err != nil {
+			utils.CliError("Invalid --level: %s.", err)
+		}
+	}
+
+	now := time.Now()
+	if since != "" {
+		if filter.Since, err = utils.ParseTimeArg(since, now); err != nil {
+			utils.CliError("Invalid --since: %s.", err)
+		}
+	}
+	if until != "" {
+		if filter.Until, err = utils.ParseTimeArg(until, now); err != nil {
+			utils.CliError("Invalid --until: %s.", err)
+		}
+	}
+
+	if grep != "" {
+		if filter.Grep, err = regexp.Compile(grep); err != nil {
+			utils.CliError("Invalid --grep: %s.", err)
+		}
+	}
+	return filter
+}
+
+// printLogEntry prints a single log entry as one line coloured by level, for use in follow mode.
+func printLogEntry(entry log.LogEntry) {
+	level := fmt.Sprintf("%-8s", log.LevelName(entry.Level))
+	switch {
+	case entry.Level >= 40:
+		level = utils.Red(level)
+	case entry.Level >= 30:
+		level = utils.Yellow(level)
+	case entry.Level >= 20:
+		level = utils.Blue(level)
+	}
+	fmt.Printf("%s  %s  %s  %s  [%s] %s\n",
+		entry.Date.Local().Format("2006-01-02 15:04:05"), level, entry.ServerName, entry.Program, entry.Process, entry.Msg)
 }
@@ -0,0 +1,82 @@
+package log
+
+import (
+	"github.com/alpacanetworks/alpacon-cli/api/log"
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+var logExportCmd = &cobra.Command{
+	Use:   "export [SERVER NAME]",
+	Short: "Export logs as NDJSON, CSV or syslog lines",
+	Long: `
+	Export the log entries of a server, or of all servers, oldest first, for ingestion by a SIEM or log pipeline.
+	Entries are written as NDJSON, CSV or RFC 5424 syslog lines to standard output or to the file given with '--output'.
+	With '--checkpoint', the ID of the last exported entry is saved to a file after the output has been written,
+	and the next run only exports newer entries, so a cron job can pick up where it left off.
+	The checkpoint is only advanced once the entries are on disk, so an interrupted run exports them again.
+	All filters of 'alpacon log' can be used.
+	`,
+	Example: `
+	# Export all error logs of the last day as NDJSON
+	alpacon log export myserver --level ERROR --since 1d > errors.ndjson
+
+	# Append new log entries of all servers to a syslog file, from cron
+	alpacon log export --format syslog --checkpoint /var/lib/alpacon/logs.checkpoint --append -o /var/log/alpacon/logs.log
+	`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var serverName string
+		if len(args) > 0 {
+			serverName = args[0]
+		}
+		format, _ := cmd.Flags().GetString("format")
+		output, _ := cmd.Flags().GetString("output")
+		appendTo, _ := cmd.Flags().GetBool("append")
+		checkpointPath, _ := cmd.Flags().GetString("checkpoint")
+		filter := parseFilter(cmd, serverName)
+
+		if !utils.ValidExportFormat(format) {
+			utils.CliError("Invalid format '%s'. Choose one of %s.", format, strings.Join(utils.ExportFormats, ", "))
+		}
+
+		var checkpoint log.ExportCheckpoint
+		if checkpointPath != "" {
+			if _, err := utils.ReadCheckpoint(checkpointPath, &checkpoint); err != nil {
+				utils.CliError("Failed to read the checkpoint: %s.", err)
+			}
+		}
+
+		alpaconClient, err := client.NewAlpaconAPIClient()
+		if err != nil {
+			utils.CliError("Connection to Alpacon API failed: %s. Consider re-logging.", err)
+		}
+
+		entries, err := log.ListLogsAfter(alpaconClient, filter, checkpoint)
+		if err != nil {
+			utils.CliError("Failed to get logs: %s.", err)
+		}
+
+		if err = utils.ExportRecords(output, appendTo, format, entries); err != nil {
+			utils.CliError("Failed to write logs: %s.", err)
+		}
+
+		if checkpointPath != "" {
+			checkpoint.Advance(entries)
+			if err = utils.WriteCheckpoint(checkpointPath, checkpoint); err != nil {
+				utils.CliError("Failed to save the checkpoint: %s.", err)
+			}
+		}
+		utils.CliInfo("Exported %d log entries.", len(entries))
+	},
+}
+
+func init() {
+	logExportCmd.Flags().String("format", "ndjson", "Output format: "+strings.Join(utils.ExportFormats, ", "))
+	logExportCmd.Flags().StringP("output", "o", "", "Write to this file instead of standard output")
+	logExportCmd.Flags().Bool("append", false, "Append to the output file instead of replacing it")
+	logExportCmd.Flags().String("checkpoint", "", "Only export entries added since the last run, tracked in this file")
+	addFilterFlags(logExportCmd)
+}
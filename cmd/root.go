@@ -7,6 +7,9 @@ import (
 	"github.com/alpacanetworks/alpacon-cli/cmd/cert"
 	"github.com/alpacanetworks/alpacon-cli/cmd/csr"
 	"github.com/alpacanetworks/alpacon-cli/cmd/event"
+	"github.com/alpacanetworks/alpacon-cli/cmd/exporter"
+	"github.com/alpacanetworks/alpacon-cli/cmd/forward"
+	"github.com/alpacanetworks/alpacon-cli/cmd/fs"
 	"github.com/alpacanetworks/alpacon-cli/cmd/ftp"
 	"github.com/alpacanetworks/alpacon-cli/cmd/iam"
 	"github.com/alpacanetworks/alpacon-cli/cmd/log"
@@ -61,6 +64,11 @@ func init() {
 
 	// ftp
 	RootCmd.AddCommand(ftp.CpCmd)
+	RootCmd.AddCommand(ftp.SyncCmd)
+	RootCmd.AddCommand(ftp.EditCmd)
+
+	// fs
+	RootCmd.AddCommand(fs.FsCmd)
 
 	// packages
 	RootCmd.AddCommand(packages.PackagesCmd)
@@ -71,6 +79,12 @@ func init() {
 	// event
 	RootCmd.AddCommand(event.EventCmd)
 
+	// forward
+	RootCmd.AddCommand(forward.ForwardCmd)
+
+	// exporter
+	RootCmd.AddCommand(exporter.ExporterCmd)
+
 	// note
 	RootCmd.AddCommand(note.NoteCmd)
 
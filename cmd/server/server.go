@@ -23,4 +23,6 @@ func init() {
 	ServerCmd.AddCommand(serverDetailCmd)
 	ServerCmd.AddCommand(serverCreateCmd)
 	ServerCmd.AddCommand(serverDeleteCmd)
+	ServerCmd.AddCommand(serverUpdateCmd)
+	ServerCmd.AddCommand(serverWaitCmd)
 }
@@ -1,12 +1,16 @@
 package server
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/alpacanetworks/alpacon-cli/api/iam"
 	"github.com/alpacanetworks/alpacon-cli/api/server"
 	"github.com/alpacanetworks/alpacon-cli/client"
 	"github.com/alpacanetworks/alpacon-cli/utils"
 	"github.com/spf13/cobra"
+	"io"
+	"os"
+	"path/filepath"
 	"strings"
 )
 
@@ -16,31 +20,192 @@ var serverCreateCmd = &cobra.Command{
 	Long: `
 	Create a new server with specific configurations. This command allows you to set up a server with a unique name, 
 	choose a platform, and define access permissions for different groups. 
+	Without flags, the server details are prompted for. For automation, give them with '--name', '--platform'
+	and '--groups', or create many servers at once from a YAML manifest with '-f'.
+	The install instructions can be printed as JSON with '-o json', or written as scripts named after
+	each server to a directory with '--script-dir'. If a script cannot be written, its instructions
+	are printed instead, as they cannot be obtained again.
 	`,
-	Example: `alpacon create server`,
+	Example: `
+	alpacon server create
+	alpacon server create --name web-01 --platform debian --groups admin,developers
+	alpacon server create --name web-01 --platform debian --groups admin -o json
+	alpacon server create -f servers.yaml --script-dir ./install
+
+	# servers.yaml
+	servers:
+	  - name: web-01
+	    platform: debian
+	    groups: [admin, developers]
+	  - name: db-01
+	    platform: rhel
+	    groups: [admin]
+	`,
+	Args: cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
+		manifestPath, _ := cmd.Flags().GetString("file")
+		output, _ := cmd.Flags().GetString("output")
+		scriptDir, _ := cmd.Flags().GetString("script-dir")
+
+		if output != "" && output != "json" {
+			utils.CliError("Invalid output format '%s'. Only json is supported.", output)
+		}
+		flagsGiven := cmd.Flags().Changed("name") || cmd.Flags().Changed("platform") || cmd.Flags().Changed("groups")
+		if manifestPath != "" && flagsGiven {
+			utils.CliError("Give the servers either with '-f' or with '--name', '--platform' and '--groups', not both.")
+		}
+
+		var specs []server.ServerSpec
+		if manifestPath != "" {
+			data, err := readManifest(manifestPath)
+			if err != nil {
+				utils.CliError("Failed to read the manifest: %s.", err)
+			}
+			specs, err = server.ParseManifest(data)
+			if err != nil {
+				utils.CliError("Invalid manifest %s: %s.", manifestPath, err)
+			}
+		} else if flagsGiven {
+			specs = []server.ServerSpec{parseCreateFlags(cmd)}
+		}
 
 		alpaconClient, err := client.NewAlpaconAPIClient()
 		if err != nil {
 			utils.CliError("Connection to Alpacon API failed: %s. Consider re-logging.", err)
 		}
 
-		groupList, err := iam.GetGroupList(alpaconClient)
-		if err != nil {
-			utils.CliError("Failed to retrieve the group list: %s.", err)
+		var requests []server.ServerRequest
+		if specs == nil {
+			groupList, err := iam.GetGroupList(alpaconClient)
+			if err != nil {
+				utils.CliError("Failed to retrieve the group list: %s.", err)
+			}
+			requests = append(requests, promptForServer(alpaconClient, groupList))
+		} else {
+			requests = serverRequests(alpaconClient, specs)
 		}
 
-		serverRequest := promptForServer(alpaconClient, groupList)
+		var responses []server.ServerCreatedResponse
+		failed, scriptsFailed := 0, 0
+		for _, serverRequest := range requests {
+			response, err := server.CreateServer(alpaconClient, serverRequest)
+			if err != nil {
+				utils.CliWarning("Failed to create the server %s: %s.", serverRequest.Name, err)
+				failed++
+				continue
+			}
+			responses = append(responses, response)
 
-		response, err := server.CreateServer(alpaconClient, serverRequest)
-		if err != nil {
-			utils.CliError("Failed to create the new server: %s.", err)
+			if scriptDir != "" {
+				scriptPath, err := writeInstallScript(scriptDir, response)
+				if err == nil {
+					utils.CliInfo("Install script for %s written to %s.", response.Name, scriptPath)
+					continue
+				}
+				// The instructions cannot be obtained again, so show them rather than losing them.
+				utils.CliWarning("Failed to write the install script of %s: %s.", response.Name, err)
+				scriptsFailed++
+			}
+			if output == "" {
+				if len(requests) > 1 {
+					utils.CliInfo("Server %s created.", response.Name)
+				}
+				installServerInfo(response)
+			}
 		}
 
-		installServerInfo(response)
+		if output == "json" {
+			printCreatedServers(responses, manifestPath != "")
+		}
+		if failed > 0 {
+			utils.CliError("Failed to create %d of %d server(s).", failed, len(requests))
+		}
+		if scriptsFailed > 0 {
+			utils.CliError("Failed to write %d of %d install script(s).", scriptsFailed, len(responses))
+		}
 	},
 }
 
+func init() {
+	serverCreateCmd.Flags().String("name", "", "Name of the server")
+	serverCreateCmd.Flags().String("platform", "", "Platform of the server: debian or rhel")
+	serverCreateCmd.Flags().StringSlice("groups", nil, "Groups authorized to access the server (e.g. admin,developers)")
+	serverCreateCmd.Flags().StringP("file", "f", "", "YAML manifest of the servers to create ('-' for standard input)")
+	serverCreateCmd.Flags().StringP("output", "o", "", "Print the created servers and install instructions in this format: json")
+	serverCreateCmd.Flags().String("script-dir", "", "Write the install script of each server to [SERVER NAME].sh in this directory")
+}
+
+func parseCreateFlags(cmd *cobra.Command) server.ServerSpec {
+	name, _ := cmd.Flags().GetString("name")
+	platform, _ := cmd.Flags().GetString("platform")
+	groups, _ := cmd.Flags().GetStringSlice("groups")
+
+	spec := server.ServerSpec{Name: name, Platform: strings.ToLower(platform), Groups: groups}
+	if err := spec.Validate(); err != nil {
+		utils.CliError("Invalid server: %s.", err)
+	}
+	return spec
+}
+
+func readManifest(manifestPath string) ([]byte, error) {
+	if manifestPath == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return utils.ReadFileFromPath(manifestPath)
+}
+
+// serverRequests resolves the group names of specs, looking each group up once.
+func serverRequests(ac *client.AlpaconClient, specs []server.ServerSpec) []server.ServerRequest {
+	resolved := map[string]string{}
+	var requests []server.ServerRequest
+	for _, spec := range specs {
+		serverRequest := server.ServerRequest{Name: spec.Name, Platform: spec.Platform}
+		for _, groupName := range spec.Groups {
+			if _, ok := resolved[groupName]; !ok {
+				resolved[groupName] = groupIDs(ac, []string{groupName})[0]
+			}
+			serverRequest.Groups = append(serverRequest.Groups, resolved[groupName])
+		}
+		requests = append(requests, serverRequest)
+	}
+	return requests
+}
+
+// writeInstallScript writes the install script of response to dir, readable only by the owner
+// because it carries the registration secret of the server.
+func writeInstallScript(dir string, response server.ServerCreatedResponse) (string, error) {
+	if response.Name == "" || strings.ContainsAny(response.Name, `/\`) || strings.Contains(response.Name, "..") {
+		return "", fmt.Errorf("the server name cannot be used as a file name")
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	scriptPath := filepath.Join(dir, response.Name+".sh")
+	script := "#!/bin/sh\n# Installs the alpamon agent for " + response.Name + ".\n" + response.Instruction1 + "\n"
+	return scriptPath, os.WriteFile(scriptPath, []byte(script), 0700)
+}
+
+// printCreatedServers prints responses as JSON: an array for a manifest, otherwise the single server.
+// Unlike utils.PrintJson, newlines in the instructions stay escaped, so the output can be parsed.
+func printCreatedServers(responses []server.ServerCreatedResponse, manifest bool) {
+	var data []byte
+	var err error
+	if manifest {
+		if responses == nil {
+			responses = []server.ServerCreatedResponse{}
+		}
+		data, err = json.MarshalIndent(responses, "", "    ")
+	} else if len(responses) == 1 {
+		data, err = json.MarshalIndent(responses[0], "", "    ")
+	} else {
+		return
+	}
+	if err != nil {
+		utils.CliError("Failed to encode the created servers: %s.", err)
+	}
+	fmt.Println(string(data))
+}
+
 func promptForServer(ac *client.AlpaconClient, groupList []iam.GroupAttributes) server.ServerRequest {
 	var serverRequest server.ServerRequest
 
@@ -1,12 +1,22 @@
 package server
 
 import (
+	"encoding/json"
+	"fmt"
+	"github.com/alpacanetworks/alpacon-cli/api/event"
+	"github.com/alpacanetworks/alpacon-cli/api/log"
+	"github.com/alpacanetworks/alpacon-cli/api/note"
 	"github.com/alpacanetworks/alpacon-cli/api/server"
 	"github.com/alpacanetworks/alpacon-cli/client"
 	"github.com/alpacanetworks/alpacon-cli/utils"
 	"github.com/spf13/cobra"
+	"strings"
+	"time"
 )
 
+// describeRecent is the number of notes, events and error logs shown by describe.
+const describeRecent = 5
+
 var serverDetailCmd = &cobra.Command{
 	Use:     "describe [SERVER NAME]",
 	Aliases: []string{"desc"},
@@ -15,14 +25,24 @@ var serverDetailCmd = &cobra.Command{
 	The describe command fetches and displays detailed information about a specific server, 
 	including its status, and other relevant attributes. 
 	This command is useful for getting an in-depth understanding of a server's current state and configuration.
+	Besides the status, hardware, OS, uptime and groups of the server, it shows its recent notes, command events
+	and error logs. Use '-o json' to print the raw server details instead.
 	`,
 	Example: ` 
 	# Display details of a server named 'myserver'
   	alpacon server describe myserver
+
+	# Print the raw details as JSON
+	alpacon server describe myserver -o json
 	`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		serverName := args[0]
+		output, _ := cmd.Flags().GetString("output")
+
+		if output != "" && output != "json" {
+			utils.CliError("Invalid output format '%s'. Only json is supported.", output)
+		}
 
 		alpaconClient, err := client.NewAlpaconAPIClient()
 		if err != nil {
@@ -34,6 +54,158 @@ var serverDetailCmd = &cobra.Command{
 			utils.CliError("Failed to retrieve the server details: %s.", err)
 		}
 
-		utils.PrintJson(serverDetail)
+		if output == "json" {
+			utils.PrintJson(serverDetail)
+			return
+		}
+
+		var details server.ServerDetails
+		if err = json.Unmarshal(serverDetail, &details); err != nil {
+			utils.CliError("Failed to parse the server details: %s.", err)
+		}
+
+		printServerDetail(details)
+		printRecentActivity(alpaconClient, details.Name)
 	},
 }
+
+func init() {
+	serverDetailCmd.Flags().StringP("output", "o", "", "Print the raw server details in this format: json")
+}
+
+// printServerDetail prints the status, hardware and configuration of s.
+func printServerDetail(s server.ServerDetails) {
+	cpu := s.CPUType
+	if s.CPUPhysicalCores > 0 || s.CPULogicalCores > 0 {
+		cpu = strings.TrimSpace(fmt.Sprintf("%s (%d cores, %d threads)", s.CPUType, s.CPUPhysicalCores, s.CPULogicalCores))
+	}
+
+	var memory string
+	if s.PhysicalMemory > 0 {
+		memory = utils.FormatBytes(s.PhysicalMemory)
+	}
+
+	var uptime string
+	if !s.BootTime.IsZero() {
+		uptime = fmt.Sprintf("%s (booted %s)", formatUptime(time.Since(s.BootTime)), s.BootTime.Local().Format(time.RFC3339))
+	}
+
+	var delay string
+	if s.IsConnected {
+		delay = fmt.Sprintf("now %.3f, 1h %.3f, 1d %.3f, 1w %.3f",
+			s.Status.Meta.DelayNow, s.Status.Meta.Delay1h, s.Status.Meta.Delay1d, s.Status.Meta.Delay1w)
+	}
+
+	fields := [][2]string{
+		{"Name", s.Name},
+		{"ID", s.ID},
+		{"Status", colorStatus(s.Status)},
+		{"Connected", yesNo(s.IsConnected)},
+		{"Commissioned", yesNo(s.Commissioned)},
+		{"Delay", delay},
+		{"Load", fmt.Sprintf("%.2f", s.Load)},
+		{"IP", s.RemoteIP},
+		{"OS", strings.TrimSpace(s.OSName + " " + s.OSVersion)},
+		{"CPU", cpu},
+		{"Memory", memory},
+		{"Uptime", uptime},
+		{"Owner", s.OwnerName},
+		{"Groups", strings.Join(s.GroupsName, ", ")},
+		{"Starred", yesNo(s.Starred)},
+	}
+	for _, field := range fields {
+		if field[1] == "" {
+			continue
+		}
+		fmt.Printf("%-14s%s\n", field[0]+":", field[1])
+	}
+	for _, message := range s.Status.Messages {
+		fmt.Printf("%-14s- %s\n", "", message)
+	}
+}
+
+// printRecentActivity prints the latest notes, command events and error logs of serverName.
+// These are supplementary, so failures are reported as warnings.
+func printRecentActivity(ac *client.AlpaconClient, serverName string) {
+	fmt.Println()
+	utils.PrintHeader("Notes")
+	notes, err := note.ListServerNotes(ac, serverName, describeRecent)
+	switch {
+	case err != nil:
+		utils.CliWarning("Failed to get the notes: %s.", err)
+	case len(notes) == 0:
+		fmt.Println("No notes.")
+	default:
+		for _, n := range notes {
+			fmt.Printf("- %s (%s)\n", strings.TrimSpace(n.Content), n.Author)
+		}
+	}
+
+	fmt.Println()
+	utils.PrintHeader("Recent events")
+	events, err := event.GetEventList(ac, describeRecent, event.EventFilter{ServerName: serverName})
+	switch {
+	case err != nil:
+		utils.CliWarning("Failed to get the events: %s.", err)
+	case len(events) == 0:
+		fmt.Println("No events.")
+	default:
+		utils.PrintTable(events)
+	}
+
+	fmt.Println()
+	utils.PrintHeader("Recent error logs")
+	logs, err := log.GetSystemLogList(ac, describeRecent, log.LogFilter{ServerName: serverName, MinLevel: 40})
+	switch {
+	case err != nil:
+		utils.CliWarning("Failed to get the logs: %s.", err)
+	case len(logs) == 0:
+		fmt.Println("No error logs.")
+	default:
+		utils.PrintTable(logs)
+	}
+}
+
+// colorStatus returns the status text in the colour Alpacon shows it in.
+func colorStatus(status server.ServerStatus) string {
+	text := status.Text
+	if text == "" {
+		text = status.Code
+	}
+
+	switch strings.ToLower(status.Color) {
+	case "green", "success":
+		return utils.Green(text)
+	case "red", "danger", "error":
+		return utils.Red(text)
+	case "yellow", "orange", "warning":
+		return utils.Yellow(text)
+	case "blue", "info", "primary":
+		return utils.Blue(text)
+	default:
+		return text
+	}
+}
+
+// formatUptime formats d in days, hours and minutes.
+func formatUptime(d time.Duration) string {
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%d days %d hours", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%d hours %d minutes", hours, minutes)
+	default:
+		return fmt.Sprintf("%d minutes", minutes)
+	}
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
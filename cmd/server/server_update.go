@@ -0,0 +1,97 @@
+package server
+
+import (
+	"github.com/alpacanetworks/alpacon-cli/api/iam"
+	"github.com/alpacanetworks/alpacon-cli/api/server"
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"github.com/spf13/cobra"
+)
+
+var serverUpdateCmd = &cobra.Command{
+	Use:   "update [SERVER NAME]",
+	Short: "Update the server information",
+	Long: `
+	Update the server information in the Alpacon.
+	Without flags, the server details are opened in your editor as JSON, and the edited fields are submitted.
+	Use '--name' to rename the server, '--groups' to replace the groups authorized to access it,
+	and '--starred' or '--starred=false' to star or unstar it, without an editor.
+	Read-only fields and fields you lack the privileges for are not changed,
+	so the updated server information is printed for verification.
+	`,
+	Example: `
+	alpacon server update myserver
+	alpacon server update myserver --name web-01
+	alpacon server update myserver --groups admin,developers
+	alpacon server update myserver --starred
+	alpacon server update myserver --starred=false
+	`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		serverName := args[0]
+
+		alpaconClient, err := client.NewAlpaconAPIClient()
+		if err != nil {
+			utils.CliError("Connection to Alpacon API failed: %s. Consider re-logging.", err)
+		}
+
+		var serverDetail []byte
+		if cmd.Flags().Changed("name") || cmd.Flags().Changed("groups") || cmd.Flags().Changed("starred") {
+			serverDetail, err = server.PatchServer(alpaconClient, serverName, parseUpdateFlags(cmd, alpaconClient))
+		} else {
+			serverDetail, err = server.UpdateServer(alpaconClient, serverName)
+		}
+		if err != nil {
+			utils.CliError("Failed to update the server info: %s.", err)
+		}
+
+		utils.CliInfo("%s server successfully updated to alpacon.", serverName)
+		utils.PrintJson(serverDetail)
+	},
+}
+
+func init() {
+	serverUpdateCmd.Flags().String("name", "", "New name of the server")
+	serverUpdateCmd.Flags().StringSlice("groups", nil, "Groups authorized to access the server, replacing the current ones (e.g. admin,developers)")
+	serverUpdateCmd.Flags().Bool("starred", false, "Star the server, or unstar it with --starred=false")
+}
+
+func parseUpdateFlags(cmd *cobra.Command, ac *client.AlpaconClient) server.ServerUpdateRequest {
+	var updateRequest server.ServerUpdateRequest
+
+	if cmd.Flags().Changed("name") {
+		name, _ := cmd.Flags().GetString("name")
+		if name == "" {
+			utils.CliError("The server name must not be empty.")
+		}
+		updateRequest.Name = &name
+	}
+
+	if cmd.Flags().Changed("groups") {
+		groupNames, _ := cmd.Flags().GetStringSlice("groups")
+		if len(groupNames) == 0 {
+			utils.CliError("At least one group must be authorized to access the server.")
+		}
+		updateRequest.Groups = groupIDs(ac, groupNames)
+	}
+
+	if cmd.Flags().Changed("starred") {
+		starred, _ := cmd.Flags().GetBool("starred")
+		updateRequest.Starred = &starred
+	}
+
+	return updateRequest
+}
+
+// groupIDs resolves group names to IDs.
+func groupIDs(ac *client.AlpaconClient, groupNames []string) []string {
+	var ids []string
+	for _, groupName := range groupNames {
+		groupID, err := iam.GetGroupIDByName(ac, groupName)
+		if err != nil {
+			utils.CliError("Failed to find the group '%s': %s.", groupName, err)
+		}
+		ids = append(ids, groupID)
+	}
+	return ids
+}
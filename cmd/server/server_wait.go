@@ -0,0 +1,58 @@
+package server
+
+import (
+	"fmt"
+	"github.com/alpacanetworks/alpacon-cli/api/server"
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"github.com/spf13/cobra"
+	"strings"
+	"time"
+)
+
+var serverWaitCmd = &cobra.Command{
+	Use:   "wait [SERVER NAME]",
+	Short: "Wait until a server's agent has connected or the server is commissioned",
+	Long: `
+	Block until the agent of a server connects, or until the server is commissioned, printing each change
+	of its status. Use it after running the install script to gate provisioning pipelines on agent registration.
+	The command exits with a non-zero status if the condition is not met within '--timeout'.
+	`,
+	Example: `
+	alpacon server wait myserver
+	alpacon server wait myserver --for commissioned --timeout 10m
+	`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		serverName := args[0]
+		condition, _ := cmd.Flags().GetString("for")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		interval, _ := cmd.Flags().GetDuration("interval")
+
+		if timeout <= 0 || interval <= 0 {
+			utils.CliError("The timeout and the interval must be positive.")
+		}
+
+		alpaconClient, err := client.NewAlpaconAPIClient()
+		if err != nil {
+			utils.CliError("Connection to Alpacon API failed: %s. Consider re-logging.", err)
+		}
+
+		start := time.Now()
+		_, err = server.WaitForServer(alpaconClient, serverName, condition, timeout, interval, func(details server.ServerDetails) {
+			fmt.Printf("%s  connected=%t  commissioned=%t  status=%s\n",
+				time.Now().Format("15:04:05"), details.IsConnected, details.Commissioned, details.Status.Text)
+		})
+		if err != nil {
+			utils.CliError("%s.", err)
+		}
+
+		utils.CliInfo("%s is %s after %s.", serverName, condition, time.Since(start).Round(time.Second))
+	},
+}
+
+func init() {
+	serverWaitCmd.Flags().String("for", "connected", "Condition to wait for: "+strings.Join(server.WaitConditions, " or "))
+	serverWaitCmd.Flags().Duration("timeout", 10*time.Minute, "How long to wait before giving up")
+	serverWaitCmd.Flags().Duration("interval", 5*time.Second, "How often to check the server")
+}
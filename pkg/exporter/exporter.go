@@ -0,0 +1,214 @@
+package exporter
+
+import (
+	"bytes"
+	"github.com/alpacanetworks/alpacon-cli/api/cert"
+	"github.com/alpacanetworks/alpacon-cli/api/server"
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Snapshot is the fleet state collected in one refresh.
+type Snapshot struct {
+	Servers      []server.ServerDetails
+	Certificates []cert.Certificate
+	Authorities  []cert.AuthorityResponse
+}
+
+// Exporter collects the fleet state periodically and serves it as Prometheus metrics.
+// Scrapes are answered from the last collection, so they never wait for the Alpacon API.
+type Exporter struct {
+	ac *client.AlpaconClient
+
+	mu          sync.RWMutex
+	snapshot    Snapshot
+	success     bool
+	lastSuccess time.Time
+	duration    time.Duration
+}
+
+func NewExporter(ac *client.AlpaconClient) *Exporter {
+	return &Exporter{ac: ac}
+}
+
+// Run refreshes the metrics every interval, forever, starting one interval from now, as the caller
+// does the initial refresh. A failed refresh keeps the previous metrics and is reported by alpacon_exporter_refresh_success.
+func (e *Exporter) Run(interval time.Duration) {
+	for {
+		time.Sleep(interval)
+		if err := e.Refresh(); err != nil {
+			utils.CliWarning("Failed to refresh metrics: %s.", err)
+		}
+	}
+}
+
+// Refresh collects the fleet state from the Alpacon API.
+func (e *Exporter) Refresh() error {
+	start := time.Now()
+	snapshot, err := e.collect()
+	duration := time.Since(start)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.duration = duration
+	e.success = err == nil
+	if err == nil {
+		e.snapshot = snapshot
+		e.lastSuccess = time.Now()
+	}
+	return err
+}
+
+func (e *Exporter) collect() (Snapshot, error) {
+	var snapshot Snapshot
+	if err := e.ac.RefreshAccessTokenIfExpired(); err != nil {
+		return snapshot, err
+	}
+
+	var err error
+	if snapshot.Servers, err = server.ListServers(e.ac); err != nil {
+		return snapshot, err
+	}
+	if snapshot.Certificates, err = cert.ListCertificates(e.ac); err != nil {
+		return snapshot, err
+	}
+	if snapshot.Authorities, err = cert.ListAuthorities(e.ac); err != nil {
+		return snapshot, err
+	}
+	return snapshot, nil
+}
+
+// ServeHTTP writes the metrics of the last collection.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mu.RLock()
+	var buf bytes.Buffer
+	m := &metricWriter{w: &buf}
+	writeSnapshot(m, e.snapshot)
+	writeExporterMetrics(m, e.success, e.lastSuccess, e.duration)
+	e.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write(buf.Bytes())
+}
+
+func writeExporterMetrics(m *metricWriter, success bool, lastSuccess time.Time, duration time.Duration) {
+	m.family("alpacon_exporter_refresh_success", "gauge", "Whether the last refresh from the Alpacon API succeeded.")
+	m.sample("alpacon_exporter_refresh_success", nil, boolValue(success))
+
+	if !lastSuccess.IsZero() {
+		m.family("alpacon_exporter_last_success_timestamp_seconds", "gauge", "Time of the last successful refresh.")
+		m.sample("alpacon_exporter_last_success_timestamp_seconds", nil, float64(lastSuccess.Unix()))
+	}
+
+	m.family("alpacon_exporter_refresh_duration_seconds", "gauge", "Duration of the last refresh.")
+	m.sample("alpacon_exporter_refresh_duration_seconds", nil, duration.Seconds())
+}
+
+func writeSnapshot(m *metricWriter, snapshot Snapshot) {
+	servers := snapshot.Servers
+	serverGauge := func(name, help string, value func(server.ServerDetails) float64) {
+		m.family(name, "gauge", help)
+		for _, s := range servers {
+			m.sample(name, map[string]string{"server": s.Name}, value(s))
+		}
+	}
+
+	m.family("alpacon_server_info", "gauge", "Static information about the server, always 1.")
+	for _, s := range servers {
+		m.sample("alpacon_server_info", map[string]string{
+			"server":   s.Name,
+			"id":       s.ID,
+			"os":       osName(s),
+			"cpu_type": s.CPUType,
+			"owner":    s.OwnerName,
+			"groups":   strings.Join(s.GroupsName, ","),
+		}, 1)
+	}
+	serverGauge("alpacon_server_connected", "Whether the agent of the server is connected.", func(s server.ServerDetails) float64 {
+		return boolValue(s.IsConnected)
+	})
+	serverGauge("alpacon_server_commissioned", "Whether the server has been commissioned.", func(s server.ServerDetails) float64 {
+		return boolValue(s.Commissioned)
+	})
+	serverGauge("alpacon_server_load", "Load of the server as reported by its agent.", func(s server.ServerDetails) float64 {
+		return s.Load
+	})
+
+	m.family("alpacon_server_delay", "gauge", "Response delay of the agent as reported by Alpacon, averaged over the window.")
+	for _, s := range servers {
+		delays := map[string]float64{
+			"now": s.Status.Meta.DelayNow,
+			"1h":  s.Status.Meta.Delay1h,
+			"1d":  s.Status.Meta.Delay1d,
+			"1w":  s.Status.Meta.Delay1w,
+		}
+		for _, window := range []string{"now", "1h", "1d", "1w"} {
+			m.sample("alpacon_server_delay", map[string]string{"server": s.Name, "window": window}, delays[window])
+		}
+	}
+
+	m.family("alpacon_server_boot_time_seconds", "gauge", "Boot time of the server as a Unix timestamp.")
+	for _, s := range servers {
+		if !s.BootTime.IsZero() {
+			m.sample("alpacon_server_boot_time_seconds", map[string]string{"server": s.Name}, float64(s.BootTime.Unix()))
+		}
+	}
+	serverGauge("alpacon_server_cpu_physical_cores", "Number of physical CPU cores.", func(s server.ServerDetails) float64 {
+		return float64(s.CPUPhysicalCores)
+	})
+	serverGauge("alpacon_server_cpu_logical_cores", "Number of logical CPU cores.", func(s server.ServerDetails) float64 {
+		return float64(s.CPULogicalCores)
+	})
+	serverGauge("alpacon_server_memory_bytes", "Physical memory of the server in bytes.", func(s server.ServerDetails) float64 {
+		return float64(s.PhysicalMemory)
+	})
+
+	m.family("alpacon_servers", "gauge", "Number of servers by operating system and connection state.")
+	counts := map[[2]string]int{}
+	for _, s := range servers {
+		counts[[2]string{osName(s), strconv.FormatBool(s.IsConnected)}]++
+	}
+	var keys [][2]string
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i][0] < keys[j][0] || keys[i][0] == keys[j][0] && keys[i][1] < keys[j][1]
+	})
+	for _, key := range keys {
+		m.sample("alpacon_servers", map[string]string{"os": key[0], "connected": key[1]}, float64(counts[key]))
+	}
+
+	authorityNames := map[string]string{}
+	m.family("alpacon_authority_expiry_timestamp_seconds", "gauge", "Expiry time of the root certificate of the authority as a Unix timestamp.")
+	for _, a := range snapshot.Authorities {
+		authorityNames[a.Id] = a.Name
+		if !a.ExpiresAt.IsZero() {
+			m.sample("alpacon_authority_expiry_timestamp_seconds", map[string]string{"authority": a.Name, "id": a.Id},
+				float64(a.ExpiresAt.Unix()))
+		}
+	}
+
+	m.family("alpacon_certificate_expiry_timestamp_seconds", "gauge", "Expiry time of the certificate as a Unix timestamp.")
+	for _, c := range snapshot.Certificates {
+		if c.ExpiresAt.IsZero() {
+			continue
+		}
+		authority := authorityNames[c.Authority]
+		if authority == "" {
+			authority = c.Authority
+		}
+		m.sample("alpacon_certificate_expiry_timestamp_seconds", map[string]string{"id": c.Id, "authority": authority},
+			float64(c.ExpiresAt.Unix()))
+	}
+}
+
+func osName(s server.ServerDetails) string {
+	return strings.TrimSpace(s.OSName + " " + s.OSVersion)
+}
@@ -0,0 +1,42 @@
+package exporter
+
+import (
+	"bytes"
+	"github.com/alpacanetworks/alpacon-cli/api/cert"
+	"github.com/alpacanetworks/alpacon-cli/api/server"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestWriteSnapshot(t *testing.T) {
+	boot := time.Unix(1700000000, 0)
+	snapshot := Snapshot{
+		Servers: []server.ServerDetails{
+			{Name: "web", OSName: "Ubuntu", OSVersion: "22.04", IsConnected: true, Load: 0.5, BootTime: boot,
+				PhysicalMemory: 8 << 30, Status: server.ServerStatus{Meta: server.ServerStatusMeta{Delay1h: 0.25}}},
+			{Name: "db", OSName: "Ubuntu", OSVersion: "22.04"},
+			{Name: `odd"name`, OSName: "Debian", OSVersion: "12", IsConnected: true},
+		},
+		Authorities:  []cert.AuthorityResponse{{Id: "a1", Name: "internal", ExpiresAt: time.Unix(1800000000, 0)}},
+		Certificates: []cert.Certificate{{Id: "c1", Authority: "a1", ExpiresAt: time.Unix(1750000000, 0)}},
+	}
+
+	var buf bytes.Buffer
+	writeSnapshot(&metricWriter{w: &buf}, snapshot)
+	out := buf.String()
+
+	assert.Contains(t, out, "# TYPE alpacon_server_connected gauge\n")
+	assert.Contains(t, out, `alpacon_server_connected{server="web"} 1`+"\n")
+	assert.Contains(t, out, `alpacon_server_connected{server="db"} 0`+"\n")
+	assert.Contains(t, out, `alpacon_server_connected{server="odd\"name"} 1`+"\n")
+	assert.Contains(t, out, `alpacon_server_load{server="web"} 0.5`+"\n")
+	assert.Contains(t, out, `alpacon_server_delay{server="web",window="1h"} 0.25`+"\n")
+	assert.Contains(t, out, `alpacon_server_boot_time_seconds{server="web"} 1.7e+09`+"\n")
+	assert.NotContains(t, out, `alpacon_server_boot_time_seconds{server="db"}`)
+	assert.Contains(t, out, `alpacon_server_memory_bytes{server="web"} 8.589934592e+09`+"\n")
+	assert.Contains(t, out, `alpacon_servers{connected="false",os="Ubuntu 22.04"} 1`+"\n")
+	assert.Contains(t, out, `alpacon_servers{connected="true",os="Ubuntu 22.04"} 1`+"\n")
+	assert.Contains(t, out, `alpacon_authority_expiry_timestamp_seconds{authority="internal",id="a1"} 1.8e+09`+"\n")
+	assert.Contains(t, out, `alpacon_certificate_expiry_timestamp_seconds{authority="internal",id="c1"} 1.75e+09`+"\n")
+}
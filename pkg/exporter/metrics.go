@@ -0,0 +1,71 @@
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// metricWriter writes metrics in the Prometheus text exposition format (version 0.0.4).
+type metricWriter struct {
+	w   io.Writer
+	err error
+}
+
+// family starts a metric family. Its samples must follow before the next family starts.
+func (m *metricWriter) family(name, metricType, help string) {
+	m.printf("# HELP %s %s\n# TYPE %s %s\n", name, strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help), name, metricType)
+}
+
+// sample writes one sample of the current family. Labels are written sorted by name.
+func (m *metricWriter) sample(name string, labels map[string]string, value float64) {
+	m.printf("%s%s %s\n", name, formatLabels(labels), formatValue(value))
+}
+
+func (m *metricWriter) printf(format string, args ...interface{}) {
+	if m.err == nil {
+		_, m.err = fmt.Fprintf(m.w, format, args...)
+	}
+}
+
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	var names []string
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	var pairs []string
+	for _, name := range names {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, name, escape.Replace(labels[name])))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
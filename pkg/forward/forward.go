@@ -0,0 +1,169 @@
+package forward
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/alpacanetworks/alpacon-cli/api/event"
+	"github.com/alpacanetworks/alpacon-cli/api/log"
+	"github.com/alpacanetworks/alpacon-cli/client"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"time"
+)
+
+const (
+	minRetryDelay = time.Second
+	maxRetryDelay = time.Minute
+)
+
+// Options configures Run.
+type Options struct {
+	Logs        bool
+	Events      bool
+	LogFilter   log.LogFilter
+	EventFilter event.EventFilter
+	// StatePath is the file recording what has been delivered, so a restarted forwarder continues where it stopped.
+	StatePath string
+	Interval  time.Duration
+	BatchSize int
+}
+
+// State records the last delivered log entry and event.
+type State struct {
+	Logs   *log.ExportCheckpoint   `json:"logs,omitempty"`
+	Events *event.ExportCheckpoint `json:"events,omitempty"`
+}
+
+// StateFileName returns the default state file name of a forwarder sending the records selected by opts to sink.
+// Forwarders to different sinks, or of different sources or filters, get different names, so none of them
+// resumes from a checkpoint of records it never delivered.
+func StateFileName(sink string, opts Options) string {
+	key := fmt.Sprintf("%s\nlogs=%t events=%t\nserver=%s level=%d-%d",
+		sink, opts.Logs, opts.Events, opts.LogFilter.ServerName, opts.LogFilter.MinLevel, opts.LogFilter.MaxLevel)
+	sum := sha256.Sum256([]byte(key))
+	return "forward-" + hex.EncodeToString(sum[:])[:12] + ".json"
+}
+
+// Run polls for new log entries and events every interval and sends them to sink in batches, oldest first.
+// The state file is updated after each delivered batch, so delivery is at least once: a batch that was sent
+// but not yet recorded when the forwarder stopped is sent again. Without a state file, forwarding starts with
+// records added from now on, or from the filters' since time if one is set.
+// Failed polls and deliveries are retried with backoff, so Run only returns when the state cannot be read or saved,
+// or when the initial request fails.
+func Run(ac *client.AlpaconClient, sink Sink, opts Options) error {
+	var state State
+	if _, err := utils.ReadCheckpoint(opts.StatePath, &state); err != nil {
+		return err
+	}
+
+	if opts.Logs && state.Logs == nil {
+		state.Logs = &log.ExportCheckpoint{}
+		if opts.LogFilter.Since.IsZero() {
+			checkpoint, err := log.LatestCheckpoint(ac, opts.LogFilter)
+			if err != nil {
+				return err
+			}
+			state.Logs = &checkpoint
+		}
+	}
+	if opts.Events && state.Events == nil {
+		state.Events = &event.ExportCheckpoint{}
+		if opts.EventFilter.Since.IsZero() {
+			state.Events.AddedAt = time.Now()
+		}
+	}
+	save := func() error {
+		return utils.WriteCheckpoint(opts.StatePath, state)
+	}
+	if err := save(); err != nil {
+		return err
+	}
+
+	pollDelay := opts.Interval
+	for {
+		var pollErr error
+		if opts.Logs {
+			entries, err := log.ListLogsAfter(ac, opts.LogFilter, *state.Logs)
+			if err == nil {
+				err = deliver(sink, entries, opts.BatchSize, func(batch []log.LogEntry) error {
+					state.Logs.Advance(batch)
+					return save()
+				})
+				if err != nil {
+					return err
+				}
+			} else {
+				utils.CliWarning("Failed to poll logs: %s. Retrying.", err)
+				pollErr = err
+			}
+		}
+
+		if opts.Events {
+			events, err := event.ListEventsAfter(ac, opts.EventFilter, *state.Events)
+			if err == nil {
+				err = deliver(sink, events, opts.BatchSize, func(batch []event.EventDetails) error {
+					state.Events.Advance(batch)
+					return save()
+				})
+				if err != nil {
+					return err
+				}
+			} else {
+				utils.CliWarning("Failed to poll events: %s. Retrying.", err)
+				pollErr = err
+			}
+		}
+
+		if pollErr != nil {
+			if err := ac.RefreshAccessTokenIfExpired(); err != nil {
+				utils.CliWarning("%s.", err)
+			}
+			pollDelay = nextDelay(pollDelay)
+		} else {
+			pollDelay = opts.Interval
+		}
+		time.Sleep(pollDelay)
+	}
+}
+
+// deliver sends records to sink in batches of at most batchSize, retrying each batch until it is delivered,
+// and calls delivered after each batch. It only fails when delivered does.
+func deliver[T utils.ExportRecord](sink Sink, records []T, batchSize int, delivered func([]T) error) error {
+	for len(records) > 0 {
+		n := batchSize
+		if n <= 0 || n > len(records) {
+			n = len(records)
+		}
+		batch := records[:n]
+
+		exportRecords := make([]utils.ExportRecord, len(batch))
+		for i, record := range batch {
+			exportRecords[i] = record
+		}
+
+		delay := minRetryDelay
+		for {
+			err := sink.Send(exportRecords)
+			if err == nil {
+				break
+			}
+			utils.CliWarning("Failed to deliver %d record(s): %s. Retrying in %s.", len(batch), err, delay)
+			time.Sleep(delay)
+			delay = nextDelay(delay)
+		}
+
+		if err := delivered(batch); err != nil {
+			return err
+		}
+		records = records[n:]
+	}
+	return nil
+}
+
+func nextDelay(delay time.Duration) time.Duration {
+	delay *= 2
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay
+}
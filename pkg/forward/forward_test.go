@@ -0,0 +1,143 @@
+package forward
+
+import (
+	"encoding/json"
+	"github.com/alpacanetworks/alpacon-cli/api/log"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type recordingSink struct {
+	batches [][]utils.ExportRecord
+}
+
+func (s *recordingSink) Send(records []utils.ExportRecord) error {
+	s.batches = append(s.batches, records)
+	return nil
+}
+
+func (s *recordingSink) Close() error {
+	return nil
+}
+
+func testEntries() []log.LogEntry {
+	date := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	return []log.LogEntry{
+		{ID: 1, Date: date, ServerName: "web", Program: "alpamon", Level: 20, Msg: "started"},
+		{ID: 2, Date: date, ServerName: "web", Program: "alpamon", Level: 40, Msg: "failed"},
+		{ID: 3, Date: date, ServerName: "db", Program: "alpamon", Level: 40, Msg: "failed"},
+	}
+}
+
+func TestDeliverBatches(t *testing.T) {
+	sink := &recordingSink{}
+	var checkpoint log.ExportCheckpoint
+	var checkpoints []int
+
+	err := deliver(sink, testEntries(), 2, func(batch []log.LogEntry) error {
+		checkpoint.Advance(batch)
+		checkpoints = append(checkpoints, checkpoint.LastID)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, sink.batches, 2)
+	assert.Len(t, sink.batches[0], 2)
+	assert.Equal(t, []int{2, 3}, checkpoints)
+}
+
+func TestHTTPSinkBulk(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		assert.Equal(t, "/alpacon/_bulk", r.URL.Path)
+		assert.Equal(t, "application/x-ndjson", r.Header.Get("Content-Type"))
+		_, _ = w.Write([]byte(`{"errors":false,"items":[]}`))
+	}))
+	defer server.Close()
+
+	sink, err := NewSink(server.URL + "/alpacon/_bulk")
+	assert.NoError(t, err)
+	assert.NoError(t, sink.Send([]utils.ExportRecord{testEntries()[0]}))
+	assert.Contains(t, body, `{"index":{}}`+"\n"+`{"id":1,`)
+
+	rejecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"errors":true}`))
+	}))
+	defer rejecting.Close()
+
+	sink, err = NewSink(rejecting.URL + "/_bulk")
+	assert.NoError(t, err)
+	assert.Error(t, sink.Send([]utils.ExportRecord{testEntries()[0]}))
+}
+
+func TestLokiBody(t *testing.T) {
+	var records []utils.ExportRecord
+	for _, entry := range testEntries() {
+		records = append(records, entry)
+	}
+
+	body, err := lokiBody(records)
+	assert.NoError(t, err)
+
+	var request struct {
+		Streams []struct {
+			Stream map[string]string `json:"stream"`
+			Values [][2]string       `json:"values"`
+		} `json:"streams"`
+	}
+	assert.NoError(t, json.Unmarshal(body, &request))
+	assert.Len(t, request.Streams, 3)
+	assert.Equal(t, map[string]string{"job": "alpacon", "server": "db", "app": "alpamon", "severity": "error"},
+		request.Streams[0].Stream)
+	assert.Equal(t, "1710072000000000000", request.Streams[0].Values[0][0])
+}
+
+func TestSyslogSinkTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer func() { _ = listener.Close() }()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		data, _ := io.ReadAll(conn)
+		_ = conn.Close()
+		received <- string(data)
+	}()
+
+	sink, err := NewSink("syslog+tcp://" + listener.Addr().String())
+	assert.NoError(t, err)
+
+	entry := testEntries()[1]
+	entry.Msg = "failed\nTraceback"
+	assert.NoError(t, sink.Send([]utils.ExportRecord{entry, entry}))
+	assert.NoError(t, sink.Close())
+
+	message := entry.Syslog().String()
+	framed := strconv.Itoa(len(message)) + " " + message
+	assert.Equal(t, framed+framed, <-received)
+}
+
+func TestStateFileName(t *testing.T) {
+	opts := Options{Logs: true, Events: true}
+	name := StateFileName("syslog://127.0.0.1:514", opts)
+	assert.Regexp(t, `^forward-[0-9a-f]{12}\.json$`, name)
+	assert.Equal(t, name, StateFileName("syslog://127.0.0.1:514", opts))
+
+	assert.NotEqual(t, name, StateFileName("http://localhost:9200/alpacon/_bulk", opts))
+	assert.NotEqual(t, name, StateFileName("syslog://127.0.0.1:514", Options{Logs: true}))
+	filtered := opts
+	filtered.LogFilter.ServerName = "web-1"
+	assert.NotEqual(t, name, StateFileName("syslog://127.0.0.1:514", filtered))
+}
@@ -0,0 +1,161 @@
+package forward
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const httpTimeout = 30 * time.Second
+
+var severityNames = map[int]string{
+	utils.SyslogSeverityCritical: "critical",
+	utils.SyslogSeverityError:    "error",
+	utils.SyslogSeverityWarning:  "warning",
+	utils.SyslogSeverityInfo:     "info",
+	utils.SyslogSeverityDebug:    "debug",
+}
+
+// httpSink posts each batch as one request. Credentials in the URL are sent with basic authentication.
+type httpSink struct {
+	url    string
+	path   string
+	client *http.Client
+}
+
+func newHTTPSink(u *url.URL) *httpSink {
+	return &httpSink{url: u.String(), path: strings.TrimSuffix(u.Path, "/"), client: &http.Client{Timeout: httpTimeout}}
+}
+
+func (s *httpSink) Send(records []utils.ExportRecord) error {
+	var body []byte
+	var err error
+	contentType := "application/x-ndjson"
+
+	switch {
+	case strings.HasSuffix(s.path, "/_bulk"):
+		body, err = bulkBody(records)
+	case strings.HasSuffix(s.path, "/loki/api/v1/push"):
+		body, err = lokiBody(records)
+		contentType = "application/json"
+	default:
+		body, err = ndjsonBody(records)
+	}
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("User-Agent", utils.GetUserAgent())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+
+	if strings.HasSuffix(s.path, "/_bulk") {
+		// Elasticsearch reports failed documents with 200 OK.
+		var bulkResponse struct {
+			Errors bool `json:"errors"`
+		}
+		if json.Unmarshal(respBody, &bulkResponse) == nil && bulkResponse.Errors {
+			return fmt.Errorf("some documents were rejected: %s", utils.TruncateString(string(respBody), 200))
+		}
+	}
+	return nil
+}
+
+func (s *httpSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+func ndjsonBody(records []utils.ExportRecord) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, record := range records {
+		line, err := json.Marshal(record)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// bulkBody returns an Elasticsearch bulk request indexing each record into the index given by the URL.
+func bulkBody(records []utils.ExportRecord) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, record := range records {
+		line, err := json.Marshal(record)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString(`{"index":{}}` + "\n")
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// lokiBody returns a Loki push request with one stream per server, program and severity.
+func lokiBody(records []utils.ExportRecord) ([]byte, error) {
+	type stream struct {
+		Stream map[string]string `json:"stream"`
+		Values [][2]string       `json:"values"`
+	}
+	streams := map[string]*stream{}
+
+	for _, record := range records {
+		line, err := json.Marshal(record)
+		if err != nil {
+			return nil, err
+		}
+
+		message := record.Syslog()
+		labels := map[string]string{
+			"job":      "alpacon",
+			"server":   message.Hostname,
+			"app":      message.AppName,
+			"severity": severityNames[message.Severity],
+		}
+		key := labels["server"] + "\x00" + labels["app"] + "\x00" + labels["severity"]
+		if streams[key] == nil {
+			streams[key] = &stream{Stream: labels}
+		}
+		streams[key].Values = append(streams[key].Values,
+			[2]string{strconv.FormatInt(message.Timestamp.UnixNano(), 10), string(line)})
+	}
+
+	var keys []string
+	for key := range streams {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var request struct {
+		Streams []*stream `json:"streams"`
+	}
+	for _, key := range keys {
+		request.Streams = append(request.Streams, streams[key])
+	}
+	return json.Marshal(request)
+}
@@ -0,0 +1,38 @@
+package forward
+
+import (
+	"fmt"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"net/url"
+)
+
+// Sink delivers batches of records to an external system. Send either delivers the whole batch or returns an error,
+// in which case the batch is sent again later, so records may be delivered more than once.
+type Sink interface {
+	Send(records []utils.ExportRecord) error
+	Close() error
+}
+
+// NewSink returns the sink for target:
+//
+//	syslog://host[:port]      RFC 5424 messages over UDP, port 514 by default
+//	syslog+tcp://host[:port]  RFC 5424 messages over TCP with octet-counting framing (RFC 6587)
+//	http(s)://host/path       NDJSON; Elasticsearch bulk requests for paths ending in /_bulk,
+//	                          and Loki push requests for /loki/api/v1/push
+func NewSink(target string) (Sink, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "syslog", "syslog+udp":
+		return newSyslogSink("udp", u)
+	case "syslog+tcp":
+		return newSyslogSink("tcp", u)
+	case "http", "https":
+		return newHTTPSink(u), nil
+	default:
+		return nil, fmt.Errorf("unsupported sink '%s'; use syslog://, syslog+tcp://, http:// or https://", target)
+	}
+}
@@ -0,0 +1,70 @@
+package forward
+
+import (
+	"errors"
+	"fmt"
+	"github.com/alpacanetworks/alpacon-cli/utils"
+	"net"
+	"net/url"
+	"time"
+)
+
+const (
+	defaultSyslogPort = "514"
+	syslogTimeout     = 10 * time.Second
+)
+
+// syslogSink sends each record as one RFC 5424 message. The connection is reopened after a failed write.
+type syslogSink struct {
+	network string
+	address string
+	conn    net.Conn
+}
+
+func newSyslogSink(network string, u *url.URL) (*syslogSink, error) {
+	if u.Hostname() == "" {
+		return nil, errors.New("the syslog sink needs a host")
+	}
+	port := u.Port()
+	if port == "" {
+		port = defaultSyslogPort
+	}
+	return &syslogSink{network: network, address: net.JoinHostPort(u.Hostname(), port)}, nil
+}
+
+func (s *syslogSink) Send(records []utils.ExportRecord) error {
+	if s.conn == nil {
+		conn, err := net.DialTimeout(s.network, s.address, syslogTimeout)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+
+	_ = s.conn.SetWriteDeadline(time.Now().Add(syslogTimeout))
+	for _, record := range records {
+		if _, err := s.conn.Write(s.frame(record.Syslog().String())); err != nil {
+			_ = s.Close()
+			return err
+		}
+	}
+	return nil
+}
+
+// frame prepares message for the transport: one datagram per message over UDP,
+// and the message length as prefix over TCP, so messages may contain newlines.
+func (s *syslogSink) frame(message string) []byte {
+	if s.network == "tcp" {
+		return []byte(fmt.Sprintf("%d %s", len(message), message))
+	}
+	return []byte(message)
+}
+
+func (s *syslogSink) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
@@ -0,0 +1,315 @@
+package utils
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// maxUnzipSize bounds the total number of bytes Unzip writes, guarding against decompression bombs.
+	maxUnzipSize = 64 << 30
+	// maxUnzipFiles bounds the number of entries Unzip accepts.
+	maxUnzipFiles = 1 << 20
+)
+
+// Zip writes a zip archive of folderPath to w, streaming each file from disk.
+// Entries are rooted at the base name of folderPath.
+func Zip(w io.Writer, folderPath string) error {
+	zipWriter := zip.NewWriter(w)
+	folderName := filepath.Base(folderPath)
+
+	err := filepath.Walk(folderPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if path == folderPath {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(folderPath, path)
+		if err != nil {
+			return err
+		}
+
+		zipPath := filepath.Join(folderName, relPath)
+		zipPath = filepath.ToSlash(zipPath)
+
+		if info.IsDir() {
+			zipPath += "/"
+		}
+
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		header.Name = zipPath
+
+		if !info.IsDir() {
+			header.Method = zip.Deflate
+		}
+
+		writer, err := zipWriter.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() {
+			file, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+
+			_, err = io.Copy(writer, file)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		_ = zipWriter.Close()
+		return err
+	}
+
+	return zipWriter.Close()
+}
+
+// IsZipFile reports whether the file at path starts with a zip signature.
+func IsZipFile(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = file.Close() }()
+
+	signature := make([]byte, 4)
+	if _, err = io.ReadFull(file, signature); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	// Local file header, or the end of central directory record of an empty archive.
+	return string(signature) == "PK\x03\x04" || string(signature) == "PK\x05\x06", nil
+}
+
+// Unzip extracts the archive src into dest.
+// Entries must stay inside dest: absolute names, ".." components and symlinks pointing outside dest are rejected,
+// and existing symlinks in dest are never followed out of it. File modes and modification times are preserved.
+// The total extracted size and the number of entries are bounded to guard against decompression bombs.
+func Unzip(src string, dest string) error {
+	r, err := zip.OpenReader(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = r.Close() }()
+
+	if len(r.File) > maxUnzipFiles {
+		return fmt.Errorf("archive contains too many entries (%d)", len(r.File))
+	}
+
+	if err = os.MkdirAll(dest, 0755); err != nil {
+		return err
+	}
+	root, err := filepath.Abs(dest)
+	if err != nil {
+		return err
+	}
+	if root, err = filepath.EvalSymlinks(root); err != nil {
+		return err
+	}
+
+	type dirTime struct {
+		path    string
+		modTime time.Time
+	}
+	var dirs []dirTime
+	var written int64
+
+	for _, f := range r.File {
+		fpath, err := unzipTarget(root, f.Name)
+		if err != nil {
+			return err
+		}
+		mode := f.Mode()
+
+		switch {
+		case mode.IsDir():
+			if err = unzipDir(fpath, f); err != nil {
+				return err
+			}
+			dirs = append(dirs, dirTime{path: fpath, modTime: f.Modified})
+
+		case mode&os.ModeSymlink != 0:
+			if err = unzipSymlink(root, fpath, f); err != nil {
+				return err
+			}
+
+		case mode.IsRegular():
+			if err = os.MkdirAll(filepath.Dir(fpath), 0755); err != nil {
+				return err
+			}
+			n, err := unzipFile(fpath, f, maxUnzipSize-written)
+			if err != nil {
+				return err
+			}
+			written += n
+
+		default:
+			return fmt.Errorf("unsupported file type in archive: %s", f.Name)
+		}
+	}
+
+	// Directory times are restored last, as creating their contents updates them.
+	// A later symlink entry may have replaced an empty directory, so never follow one here.
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if info, err := os.Lstat(dirs[i].path); err != nil || !info.IsDir() {
+			continue
+		}
+		if !dirs[i].modTime.IsZero() {
+			_ = os.Chtimes(dirs[i].path, dirs[i].modTime, dirs[i].modTime)
+		}
+	}
+	return nil
+}
+
+// unzipTarget resolves the entry name inside root, rejecting names and existing symlinks that lead outside it.
+func unzipTarget(root, name string) (string, error) {
+	cleaned := filepath.FromSlash(name)
+	if filepath.IsAbs(cleaned) || filepath.VolumeName(cleaned) != "" || strings.HasPrefix(name, "/") {
+		return "", fmt.Errorf("illegal absolute path in archive: %s", name)
+	}
+
+	target := filepath.Join(root, cleaned)
+	if !isWithin(root, target) {
+		return "", fmt.Errorf("illegal path outside the destination in archive: %s", name)
+	}
+
+	// Make sure no already existing parent directory is a symlink leading outside root.
+	parent := filepath.Dir(target)
+	for {
+		resolved, err := filepath.EvalSymlinks(parent)
+		if err == nil {
+			if !isWithin(root, resolved) {
+				return "", fmt.Errorf("illegal path through a symlink in archive: %s", name)
+			}
+			break
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+		parent = filepath.Dir(parent)
+	}
+
+	return target, nil
+}
+
+// unzipDir creates the directory entry f at fpath, refusing to follow an existing symlink there.
+func unzipDir(fpath string, f *zip.File) error {
+	if info, err := os.Lstat(fpath); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		return fmt.Errorf("illegal directory over an existing symlink in archive: %s", f.Name)
+	}
+
+	if err := os.MkdirAll(fpath, 0755); err != nil {
+		return err
+	}
+	return os.Chmod(fpath, f.Mode().Perm()|0700)
+}
+
+// unzipSymlink creates the symlink entry f at fpath if its target stays inside root.
+func unzipSymlink(root, fpath string, f *zip.File) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	target, err := io.ReadAll(io.LimitReader(rc, 4096))
+	_ = rc.Close()
+	if err != nil {
+		return err
+	}
+
+	linkTarget := filepath.FromSlash(string(target))
+	resolved := linkTarget
+	if !filepath.IsAbs(resolved) {
+		resolved = filepath.Join(filepath.Dir(fpath), resolved)
+	}
+	if !isWithin(root, resolved) {
+		return fmt.Errorf("illegal symlink pointing outside the destination in archive: %s -> %s", f.Name, string(target))
+	}
+
+	if err = os.MkdirAll(filepath.Dir(fpath), 0755); err != nil {
+		return err
+	}
+	if err = os.Remove(fpath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.Symlink(linkTarget, fpath)
+}
+
+// unzipFile writes the regular file entry f to fpath, failing once more than limit bytes would be written.
+func unzipFile(fpath string, f *zip.File, limit int64) (int64, error) {
+	// Replace rather than write through an existing symlink.
+	if info, err := os.Lstat(fpath); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		if err = os.Remove(fpath); err != nil {
+			return 0, err
+		}
+	}
+
+	perm := f.Mode().Perm()
+	if perm == 0 {
+		perm = 0644
+	}
+
+	outFile, err := os.OpenFile(fpath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return 0, err
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		_ = outFile.Close()
+		return 0, err
+	}
+
+	n, err := io.Copy(outFile, io.LimitReader(rc, limit+1))
+	_ = rc.Close()
+	if closeErr := outFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return n, fmt.Errorf("failed to extract %s: %v", f.Name, err)
+	}
+	if n > limit {
+		return n, fmt.Errorf("archive exceeds the maximum extracted size of %s", FormatBytes(maxUnzipSize))
+	}
+
+	// OpenFile applies the umask, so set the archived mode explicitly.
+	if err = os.Chmod(fpath, perm); err != nil {
+		return n, err
+	}
+	if !f.Modified.IsZero() {
+		if err = os.Chtimes(fpath, f.Modified, f.Modified); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+func isWithin(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"archive/zip"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type zipEntry struct {
+	name string
+	body string
+	mode os.FileMode
+}
+
+func writeTestZip(t *testing.T, entries []zipEntry) string {
+	archivePath := filepath.Join(t.TempDir(), "test.zip")
+	file, err := os.Create(archivePath)
+	assert.NoError(t, err)
+
+	w := zip.NewWriter(file)
+	for _, e := range entries {
+		header := &zip.FileHeader{Name: e.name, Method: zip.Deflate, Modified: time.Unix(1700000000, 0)}
+		header.SetMode(e.mode)
+		fw, err := w.CreateHeader(header)
+		assert.NoError(t, err)
+		_, err = fw.Write([]byte(e.body))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, w.Close())
+	assert.NoError(t, file.Close())
+	return archivePath
+}
+
+func TestUnzipPreservesModeAndModTime(t *testing.T) {
+	archivePath := writeTestZip(t, []zipEntry{
+		{name: "dir/", mode: os.ModeDir | 0755},
+		{name: "dir/run.sh", body: "#!/bin/sh\n", mode: 0750},
+	})
+	dest := t.TempDir()
+
+	assert.NoError(t, Unzip(archivePath, dest))
+
+	info, err := os.Stat(filepath.Join(dest, "dir", "run.sh"))
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0750), info.Mode().Perm())
+	assert.Equal(t, int64(1700000000), info.ModTime().Unix())
+}
+
+func TestUnzipRejectsPathTraversal(t *testing.T) {
+	for _, name := range []string{"../evil.txt", "dir/../../evil.txt", "/tmp/evil.txt"} {
+		archivePath := writeTestZip(t, []zipEntry{{name: name, body: "evil", mode: 0644}})
+		dest := t.TempDir()
+
+		assert.Error(t, Unzip(archivePath, dest), name)
+		_, err := os.Stat(filepath.Join(filepath.Dir(dest), "evil.txt"))
+		assert.True(t, os.IsNotExist(err), name)
+	}
+}
+
+func TestUnzipRejectsEscapingSymlink(t *testing.T) {
+	archivePath := writeTestZip(t, []zipEntry{
+		{name: "link", body: "../../etc", mode: os.ModeSymlink | 0777},
+	})
+
+	assert.Error(t, Unzip(archivePath, t.TempDir()))
+}
+
+func TestUnzipRejectsDirectoryOverSymlink(t *testing.T) {
+	dest := t.TempDir()
+	assert.NoError(t, os.Mkdir(filepath.Join(dest, "target"), 0755))
+	assert.NoError(t, os.Symlink("target", filepath.Join(dest, "dir")))
+
+	archivePath := writeTestZip(t, []zipEntry{{name: "dir/", mode: os.ModeDir | 0777}})
+
+	assert.Error(t, Unzip(archivePath, dest))
+	info, err := os.Stat(filepath.Join(dest, "target"))
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+}
+
+func TestIsZipFile(t *testing.T) {
+	archivePath := writeTestZip(t, []zipEntry{{name: "a.txt", body: "a", mode: 0644}})
+	isZip, err := IsZipFile(archivePath)
+	assert.NoError(t, err)
+	assert.True(t, isZip)
+
+	plainPath := filepath.Join(t.TempDir(), "a.txt")
+	assert.NoError(t, os.WriteFile(plainPath, []byte("PK but not really"), 0644))
+	isZip, err = IsZipFile(plainPath)
+	assert.NoError(t, err)
+	assert.False(t, isZip)
+}
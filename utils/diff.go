@@ -0,0 +1,162 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	diffContext = 3
+	// maxDiffCells bounds the size of the LCS table; larger changes are shown as a full replacement.
+	maxDiffCells = 1 << 22
+)
+
+type diffOp struct {
+	kind byte // ' ', '-' or '+'
+	text string
+}
+
+// UnifiedDiff returns the line differences between a and b in unified format with three lines of context.
+// It returns an empty string when a and b are equal.
+func UnifiedDiff(a, b []byte, aName, bName string) string {
+	ops := diffLines(splitLines(string(a)), splitLines(string(b)))
+
+	var changes []int
+	for i, op := range ops {
+		if op.kind != ' ' {
+			changes = append(changes, i)
+		}
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", aName, bName)
+
+	for i := 0; i < len(changes); {
+		// Merge changes whose context would overlap into one hunk.
+		j := i
+		for j+1 < len(changes) && changes[j+1]-changes[j] <= 2*diffContext {
+			j++
+		}
+		start, end := changes[i]-diffContext, changes[j]+diffContext+1
+		if start < 0 {
+			start = 0
+		}
+		if end > len(ops) {
+			end = len(ops)
+		}
+
+		aStart, bStart := 1, 1
+		for _, op := range ops[:start] {
+			if op.kind != '+' {
+				aStart++
+			}
+			if op.kind != '-' {
+				bStart++
+			}
+		}
+		aLen, bLen := 0, 0
+		for _, op := range ops[start:end] {
+			if op.kind != '+' {
+				aLen++
+			}
+			if op.kind != '-' {
+				bLen++
+			}
+		}
+		// An empty range refers to the line before it.
+		if aLen == 0 {
+			aStart--
+		}
+		if bLen == 0 {
+			bStart--
+		}
+
+		fmt.Fprintf(&sb, "@@ -%d,%d +%d,%d @@\n", aStart, aLen, bStart, bLen)
+		for _, op := range ops[start:end] {
+			sb.WriteByte(op.kind)
+			sb.WriteString(op.text)
+			sb.WriteByte('\n')
+		}
+		i = j + 1
+	}
+	return sb.String()
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines returns the edit script turning a into b, based on the longest common subsequence of lines.
+func diffLines(a, b []string) []diffOp {
+	var ops []diffOp
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		ops = append(ops, diffOp{' ', a[prefix]})
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	if len(midA)*len(midB) > maxDiffCells {
+		for _, line := range midA {
+			ops = append(ops, diffOp{'-', line})
+		}
+		for _, line := range midB {
+			ops = append(ops, diffOp{'+', line})
+		}
+	} else {
+		// lcs[i][j] is the length of the longest common subsequence of midA[i:] and midB[j:].
+		lcs := make([][]int, len(midA)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(midB)+1)
+		}
+		for i := len(midA) - 1; i >= 0; i-- {
+			for j := len(midB) - 1; j >= 0; j-- {
+				if midA[i] == midB[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else if lcs[i+1][j] >= lcs[i][j+1] {
+					lcs[i][j] = lcs[i+1][j]
+				} else {
+					lcs[i][j] = lcs[i][j+1]
+				}
+			}
+		}
+
+		i, j := 0, 0
+		for i < len(midA) && j < len(midB) {
+			switch {
+			case midA[i] == midB[j]:
+				ops = append(ops, diffOp{' ', midA[i]})
+				i++
+				j++
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				ops = append(ops, diffOp{'-', midA[i]})
+				i++
+			default:
+				ops = append(ops, diffOp{'+', midB[j]})
+				j++
+			}
+		}
+		for ; i < len(midA); i++ {
+			ops = append(ops, diffOp{'-', midA[i]})
+		}
+		for ; j < len(midB); j++ {
+			ops = append(ops, diffOp{'+', midB[j]})
+		}
+	}
+
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	return ops
+}
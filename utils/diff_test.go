@@ -0,0 +1,22 @@
+package utils
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	a := []byte("1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n")
+	b := []byte("1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n")
+
+	expected := "--- a/file\n+++ b/file\n" +
+		"@@ -1,6 +1,6 @@\n 1\n 2\n-3\n+three\n 4\n 5\n 6\n" +
+		"@@ -10,3 +10,4 @@\n 10\n 11\n 12\n+13\n"
+	assert.Equal(t, expected, UnifiedDiff(a, b, "a/file", "b/file"))
+}
+
+func TestUnifiedDiffEmptySides(t *testing.T) {
+	assert.Equal(t, "", UnifiedDiff([]byte("same\n"), []byte("same\n"), "a", "b"))
+	assert.Equal(t, "--- a\n+++ b\n@@ -0,0 +1,1 @@\n+new\n", UnifiedDiff(nil, []byte("new\n"), "a", "b"))
+	assert.Equal(t, "--- a\n+++ b\n@@ -1,1 +0,0 @@\n-old\n", UnifiedDiff([]byte("old\n"), nil, "a", "b"))
+}
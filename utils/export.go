@@ -0,0 +1,198 @@
+package utils
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// ExportFormats lists the formats understood by NewExportWriter.
+var ExportFormats = []string{"ndjson", "csv", "syslog"}
+
+// ValidExportFormat reports whether format is one of ExportFormats.
+func ValidExportFormat(format string) bool {
+	for _, f := range ExportFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// ExportRecord is a record that can be exported in every format. NDJSON output uses its JSON encoding.
+type ExportRecord interface {
+	CSVHeader() []string
+	CSVRecord() []string
+	Syslog() SyslogMessage
+}
+
+// ExportWriter writes records as NDJSON, CSV or RFC 5424 syslog lines.
+type ExportWriter struct {
+	format string
+	w      io.Writer
+	csv    *csv.Writer
+	header bool
+}
+
+// NewExportWriter returns a writer for format. CSV output starts with a header line unless header is false,
+// which is useful when appending to an existing file.
+func NewExportWriter(w io.Writer, format string, header bool) (*ExportWriter, error) {
+	switch format {
+	case "ndjson", "syslog":
+		return &ExportWriter{format: format, w: w}, nil
+	case "csv":
+		return &ExportWriter{format: format, w: w, csv: csv.NewWriter(w), header: header}, nil
+	default:
+		return nil, fmt.Errorf("unknown format '%s'; choose one of ndjson, csv or syslog", format)
+	}
+}
+
+func (e *ExportWriter) Write(record ExportRecord) error {
+	switch e.format {
+	case "csv":
+		if e.header {
+			if err := e.csv.Write(record.CSVHeader()); err != nil {
+				return err
+			}
+			e.header = false
+		}
+		return e.csv.Write(record.CSVRecord())
+	case "syslog":
+		_, err := io.WriteString(e.w, record.Syslog().String()+"\n")
+		return err
+	default:
+		line, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		_, err = e.w.Write(append(line, '\n'))
+		return err
+	}
+}
+
+// Flush writes any buffered data to the underlying writer.
+func (e *ExportWriter) Flush() error {
+	if e.csv == nil {
+		return nil
+	}
+	e.csv.Flush()
+	return e.csv.Error()
+}
+
+// ExportRecords writes records in format to the file at output, or to standard output if output is empty or "-".
+// The file is synced before ExportRecords returns, so a checkpoint can be saved afterwards.
+func ExportRecords[T ExportRecord](output string, appendTo bool, format string, records []T) error {
+	out, empty, err := OpenExportOutput(output, appendTo)
+	if err != nil {
+		return err
+	}
+
+	writer, err := NewExportWriter(out, format, empty)
+	if err == nil {
+		for _, record := range records {
+			if err = writer.Write(record); err != nil {
+				break
+			}
+		}
+	}
+	if err == nil {
+		err = writer.Flush()
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// OpenExportOutput opens the file at path for writing records, or standard output if path is empty or "-".
+// With appendTo, records are added to an existing file. It reports whether the output is empty,
+// so that a CSV header is written only once.
+func OpenExportOutput(path string, appendTo bool) (io.WriteCloser, bool, error) {
+	if path == "" || path == "-" {
+		return nopWriteCloser{os.Stdout}, true, nil
+	}
+
+	flag := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if appendTo {
+		flag = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+	file, err := os.OpenFile(path, flag, 0644)
+	if err != nil {
+		return nil, false, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, false, err
+	}
+	return syncCloser{file}, info.Size() == 0, nil
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// syncCloser flushes the file to disk on Close, before a checkpoint claims its records were exported.
+type syncCloser struct {
+	*os.File
+}
+
+func (f syncCloser) Close() error {
+	if err := f.File.Sync(); err != nil {
+		_ = f.File.Close()
+		return err
+	}
+	return f.File.Close()
+}
+
+// ReadCheckpoint reads the JSON checkpoint at path into v. It reports false if there is no checkpoint yet.
+func ReadCheckpoint(path string, v interface{}) (bool, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if err = json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("invalid checkpoint %s: %v", path, err)
+	}
+	return true, nil
+}
+
+// WriteCheckpoint replaces the checkpoint at path with the JSON encoding of v.
+// The file is replaced atomically, so an interrupted run leaves the previous checkpoint intact.
+func WriteCheckpoint(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err = tmp.Write(append(data, '\n')); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
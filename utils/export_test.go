@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testRecord struct {
+	Name string `json:"name"`
+}
+
+func (r testRecord) CSVHeader() []string { return []string{"name"} }
+
+func (r testRecord) CSVRecord() []string { return []string{r.Name} }
+
+func (r testRecord) Syslog() SyslogMessage {
+	return SyslogMessage{Facility: SyslogFacilityDaemon, Severity: SyslogSeverityInfo, Message: r.Name}
+}
+
+func TestSyslogMessage(t *testing.T) {
+	m := SyslogMessage{
+		Facility:  SyslogFacilityDaemon,
+		Severity:  SyslogSeverityError,
+		Timestamp: time.Date(2024, 3, 10, 12, 0, 0, 500000000, time.FixedZone("KST", 9*3600)),
+		Hostname:  "web 1",
+		AppName:   "alpamon",
+		ProcID:    "42",
+		Params:    map[string]string{"path": `C:\app "main"]`, "thread": "", "lineno": "7"},
+		Message:   "Traceback:\n  boom",
+	}
+	assert.Equal(t,
+		`<27>1 2024-03-10T03:00:00.500000Z web_1 alpamon 42 - [alpacon@32473 lineno="7" path="C:\\app \"main\"\]"] Traceback:#012  boom`,
+		m.String())
+
+	assert.Equal(t, "<14>1 - - - - - -", SyslogMessage{Facility: 1, Severity: SyslogSeverityInfo}.String())
+}
+
+func TestExportWriter(t *testing.T) {
+	records := []testRecord{{"a,b"}, {"c"}}
+	expected := map[string]string{
+		"ndjson": "{\"name\":\"a,b\"}\n{\"name\":\"c\"}\n",
+		"csv":    "name\n\"a,b\"\nc\n",
+		"syslog": "<30>1 - - - - - - a,b\n<30>1 - - - - - - c\n",
+	}
+
+	for format, want := range expected {
+		var buf bytes.Buffer
+		w, err := NewExportWriter(&buf, format, true)
+		assert.NoError(t, err)
+		for _, r := range records {
+			assert.NoError(t, w.Write(r))
+		}
+		assert.NoError(t, w.Flush())
+		assert.Equal(t, want, buf.String(), format)
+	}
+
+	_, err := NewExportWriter(&bytes.Buffer{}, "xml", true)
+	assert.Error(t, err)
+}
+
+func TestExportRecordsAppend(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "out.csv")
+
+	assert.NoError(t, ExportRecords(output, true, "csv", []testRecord{{"a"}}))
+	assert.NoError(t, ExportRecords(output, true, "csv", []testRecord{{"b"}}))
+
+	data, err := ReadFileFromPath(output)
+	assert.NoError(t, err)
+	assert.Equal(t, "name\na\nb\n", string(data))
+}
+
+func TestCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint")
+
+	var checkpoint struct {
+		LastID int `json:"last_id"`
+	}
+	found, err := ReadCheckpoint(path, &checkpoint)
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	checkpoint.LastID = 42
+	assert.NoError(t, WriteCheckpoint(path, checkpoint))
+	checkpoint.LastID = 0
+	found, err = ReadCheckpoint(path, &checkpoint)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 42, checkpoint.LastID)
+}
@@ -10,7 +10,7 @@ const (
 )
 
 func reportCLIError() {
-	fmt.Println("For issues, check the latest version or report on", gitIssueURL)
+	fmt.Fprintln(os.Stderr, "For issues, check the latest version or report on", gitIssueURL)
 }
 
 // CliError handles all error messages in the CLI.
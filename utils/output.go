@@ -17,6 +17,26 @@ func PrintTable(slice interface{}) {
 		CliError("Parsing data: Expected a list format.")
 	}
 
+	headers := make([]string, s.Type().Elem().NumField())
+	for i := 0; i < s.Type().Elem().NumField(); i++ {
+		headers[i] = s.Type().Elem().Field(i).Name
+	}
+
+	rows := make([][]string, s.Len())
+	for i := 0; i < s.Len(); i++ {
+		row := make([]string, s.Type().Elem().NumField())
+		for j := 0; j < s.Type().Elem().NumField(); j++ {
+			value := s.Index(i).Field(j)
+			row[j] = fmt.Sprintf("%v", value)
+		}
+		rows[i] = row
+	}
+
+	PrintRows(headers, rows)
+}
+
+// PrintRows prints rows in the same layout as PrintTable, for tables whose headers are only known at runtime.
+func PrintRows(headers []string, rows [][]string) {
 	table := tablewriter.NewWriter(os.Stdout)
 	table.SetAlignment(tablewriter.ALIGN_LEFT)
 	table.SetBorder(false)
@@ -36,21 +56,8 @@ func PrintTable(slice interface{}) {
 	table.SetTablePadding("\t")
 	table.SetNoWhiteSpace(true)
 
-	headers := make([]string, s.Type().Elem().NumField())
-	for i := 0; i < s.Type().Elem().NumField(); i++ {
-		headers[i] = s.Type().Elem().Field(i).Name
-	}
 	table.SetHeader(headers)
-
-	for i := 0; i < s.Len(); i++ {
-		row := make([]string, s.Type().Elem().NumField())
-		for j := 0; j < s.Type().Elem().NumField(); j++ {
-			value := s.Index(i).Field(j)
-			row[j] = fmt.Sprintf("%v", value)
-		}
-		table.Append(row)
-	}
-
+	table.AppendBulk(rows)
 	table.Render()
 }
 
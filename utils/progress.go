@@ -0,0 +1,304 @@
+package utils
+
+import (
+	"fmt"
+	"golang.org/x/term"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Progress modes accepted by NewProgress.
+const (
+	ProgressAuto  = "auto"  // bar on a terminal, plain otherwise
+	ProgressBar   = "bar"   // redrawn progress bars
+	ProgressPlain = "plain" // periodic key=value lines for logs
+	ProgressNone  = "none"  // no progress output
+)
+
+const (
+	barRefreshInterval   = 200 * time.Millisecond
+	plainRefreshInterval = 2 * time.Second
+	barWidth             = 30
+)
+
+// Progress tracks byte counts of one or more concurrent transfers and renders them to stderr.
+type Progress struct {
+	mode      string
+	out       io.Writer
+	start     time.Time
+	mu        sync.Mutex
+	active    []*Transfer
+	finished  []*Transfer
+	count     int
+	bytes     int64
+	lastLines int
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// Transfer is a single tracked transfer created by Progress.Start.
+type Transfer struct {
+	progress *Progress
+	name     string
+	total    int64
+	current  int64
+	start    time.Time
+	end      time.Time
+	once     sync.Once
+}
+
+// ValidProgressMode reports whether mode is accepted by NewProgress.
+func ValidProgressMode(mode string) bool {
+	switch mode {
+	case ProgressAuto, ProgressBar, ProgressPlain, ProgressNone:
+		return true
+	}
+	return false
+}
+
+// NewProgress creates a Progress renderer. ProgressAuto resolves to bars when stderr is a terminal.
+func NewProgress(mode string) *Progress {
+	if mode == ProgressAuto {
+		if term.IsTerminal(int(os.Stderr.Fd())) {
+			mode = ProgressBar
+		} else {
+			mode = ProgressPlain
+		}
+	}
+
+	p := &Progress{
+		mode:  mode,
+		out:   os.Stderr,
+		start: time.Now(),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+
+	if mode == ProgressNone {
+		close(p.done)
+		return p
+	}
+
+	go p.loop()
+	return p
+}
+
+// Start registers a new transfer. total may be negative when the size is unknown.
+func (p *Progress) Start(name string, total int64) *Transfer {
+	t := &Transfer{
+		progress: p,
+		name:     name,
+		total:    total,
+		start:    time.Now(),
+	}
+
+	p.mu.Lock()
+	p.active = append(p.active, t)
+	p.mu.Unlock()
+
+	return t
+}
+
+// Reader wraps r so that bytes read from it are counted towards the transfer.
+func (t *Transfer) Reader(r io.Reader) io.Reader {
+	return &progressReader{reader: r, transfer: t}
+}
+
+// Add records n additional transferred bytes.
+func (t *Transfer) Add(n int64) {
+	atomic.AddInt64(&t.current, n)
+}
+
+// SetTotal updates the expected size once it becomes known.
+func (t *Transfer) SetTotal(total int64) {
+	t.progress.mu.Lock()
+	t.total = total
+	t.progress.mu.Unlock()
+}
+
+// SetCurrent overrides the transferred byte count, e.g. when a transfer is resumed or restarted.
+func (t *Transfer) SetCurrent(n int64) {
+	atomic.StoreInt64(&t.current, n)
+}
+
+// Current returns the number of bytes transferred so far.
+func (t *Transfer) Current() int64 {
+	return atomic.LoadInt64(&t.current)
+}
+
+// Finish marks the transfer as complete. It is safe to call more than once.
+func (t *Transfer) Finish() {
+	t.once.Do(func() {
+		p := t.progress
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		t.end = time.Now()
+		for i, active := range p.active {
+			if active == t {
+				p.active = append(p.active[:i], p.active[i+1:]...)
+				break
+			}
+		}
+		p.finished = append(p.finished, t)
+		p.count++
+		p.bytes += atomic.LoadInt64(&t.current)
+	})
+}
+
+// Stop flushes pending output and ends rendering. The Progress must not be used afterwards.
+func (p *Progress) Stop() {
+	select {
+	case <-p.stop:
+	default:
+		close(p.stop)
+	}
+	<-p.done
+}
+
+// Summary prints the total number of files and bytes transferred since the Progress was created.
+func (p *Progress) Summary() {
+	p.mu.Lock()
+	count, total := p.count, p.bytes
+	p.mu.Unlock()
+
+	elapsed := time.Since(p.start)
+	switch p.mode {
+	case ProgressNone:
+		return
+	case ProgressPlain:
+		_, _ = fmt.Fprintf(p.out, "summary files=%d bytes=%d elapsed=%.1fs rate=%d\n",
+			count, total, elapsed.Seconds(), int64(rate(total, elapsed)))
+	default:
+		CliInfo("Transferred %d file(s), %s in %s (%s/s).",
+			count, FormatBytes(total), elapsed.Round(100*time.Millisecond), FormatBytes(int64(rate(total, elapsed))))
+	}
+}
+
+func (p *Progress) loop() {
+	defer close(p.done)
+
+	interval := barRefreshInterval
+	if p.mode == ProgressPlain {
+		interval = plainRefreshInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.render(false)
+		case <-p.stop:
+			p.render(true)
+			return
+		}
+	}
+}
+
+func (p *Progress) render(final bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.mode == ProgressPlain {
+		for _, t := range p.finished {
+			_, _ = fmt.Fprintln(p.out, t.plainLine("done"))
+		}
+		p.finished = nil
+		if !final {
+			for _, t := range p.active {
+				_, _ = fmt.Fprintln(p.out, t.plainLine("progress"))
+			}
+		}
+		return
+	}
+
+	var sb strings.Builder
+	if p.lastLines > 0 {
+		sb.WriteString(fmt.Sprintf("\x1b[%dA", p.lastLines))
+	}
+	// Finished transfers are printed once and scroll above the live bars.
+	for _, t := range p.finished {
+		sb.WriteString("\x1b[2K" + t.barLine() + "\n")
+	}
+	p.finished = nil
+	for _, t := range p.active {
+		sb.WriteString("\x1b[2K" + t.barLine() + "\n")
+	}
+	p.lastLines = len(p.active)
+	_, _ = io.WriteString(p.out, sb.String())
+}
+
+func (t *Transfer) elapsed() time.Duration {
+	if !t.end.IsZero() {
+		return t.end.Sub(t.start)
+	}
+	return time.Since(t.start)
+}
+
+func (t *Transfer) barLine() string {
+	current := atomic.LoadInt64(&t.current)
+	elapsed := t.elapsed()
+	speed := rate(current, elapsed)
+
+	name := t.name
+	if len(name) > 24 {
+		name = "..." + name[len(name)-21:]
+	}
+
+	if t.total <= 0 {
+		return fmt.Sprintf("%-24s %10s %10s/s %8s", name, FormatBytes(current), FormatBytes(int64(speed)),
+			elapsed.Round(time.Second))
+	}
+
+	ratio := float64(current) / float64(t.total)
+	if ratio > 1 {
+		ratio = 1
+	}
+	filled := int(ratio * barWidth)
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", barWidth-filled)
+
+	eta := "--"
+	if t.end.IsZero() && speed > 0 {
+		remaining := time.Duration(float64(t.total-current) / speed * float64(time.Second))
+		eta = "ETA " + remaining.Round(time.Second).String()
+	} else if !t.end.IsZero() {
+		eta = elapsed.Round(time.Second).String()
+	}
+
+	return fmt.Sprintf("%-24s [%s] %3.0f%% %10s %10s/s %10s", name, bar, ratio*100,
+		FormatBytes(current), FormatBytes(int64(speed)), eta)
+}
+
+func (t *Transfer) plainLine(event string) string {
+	current := atomic.LoadInt64(&t.current)
+	elapsed := t.elapsed()
+	line := fmt.Sprintf("%s name=%q bytes=%d total=%d elapsed=%.1fs rate=%d",
+		event, t.name, current, t.total, elapsed.Seconds(), int64(rate(current, elapsed)))
+	if t.total > 0 {
+		line += fmt.Sprintf(" percent=%.1f", float64(current)*100/float64(t.total))
+	}
+	return line
+}
+
+func rate(n int64, d time.Duration) float64 {
+	if d <= 0 {
+		return 0
+	}
+	return float64(n) / d.Seconds()
+}
+
+type progressReader struct {
+	reader   io.Reader
+	transfer *Transfer
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.transfer.Add(int64(n))
+	return n, err
+}
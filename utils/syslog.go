@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Syslog facilities and severities used by the exported records (RFC 5424, section 6.2.1).
+const (
+	SyslogFacilityDaemon   = 3
+	SyslogFacilityAuthPriv = 10
+
+	SyslogSeverityCritical = 2
+	SyslogSeverityError    = 3
+	SyslogSeverityWarning  = 4
+	SyslogSeverityInfo     = 6
+	SyslogSeverityDebug    = 7
+)
+
+// syslogSDID names the structured data element of exported records.
+// 32473 is the private enterprise number reserved for documentation (RFC 5612).
+const syslogSDID = "alpacon@32473"
+
+// SyslogMessage is a record to be formatted as an RFC 5424 syslog message.
+type SyslogMessage struct {
+	Facility  int
+	Severity  int
+	Timestamp time.Time
+	Hostname  string
+	AppName   string
+	ProcID    string
+	MsgID     string
+	// Params are written as the structured data element alpacon@32473, sorted by name. Empty values are left out.
+	Params  map[string]string
+	Message string
+}
+
+// String formats m as a single RFC 5424 line without a trailing newline.
+// Control characters in the message, such as the newlines of a traceback, are escaped as #ooo like rsyslog does.
+func (m SyslogMessage) String() string {
+	timestamp := "-"
+	if !m.Timestamp.IsZero() {
+		timestamp = m.Timestamp.UTC().Format("2006-01-02T15:04:05.000000Z07:00")
+	}
+
+	line := fmt.Sprintf("<%d>1 %s %s %s %s %s %s",
+		m.Facility*8+m.Severity, timestamp,
+		syslogHeaderField(m.Hostname, 255), syslogHeaderField(m.AppName, 48),
+		syslogHeaderField(m.ProcID, 128), syslogHeaderField(m.MsgID, 32),
+		m.structuredData())
+	if m.Message != "" {
+		line += " " + escapeSyslogControl(m.Message)
+	}
+	return line
+}
+
+func (m SyslogMessage) structuredData() string {
+	var names []string
+	for name, value := range m.Params {
+		if value != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "-"
+	}
+	sort.Strings(names)
+
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+	var sd strings.Builder
+	sd.WriteString("[" + syslogSDID)
+	for _, name := range names {
+		fmt.Fprintf(&sd, ` %s="%s"`, name, escape.Replace(escapeSyslogControl(m.Params[name])))
+	}
+	sd.WriteString("]")
+	return sd.String()
+}
+
+// syslogHeaderField returns value as a header field of at most limit printable ASCII characters, or - if it is empty.
+func syslogHeaderField(value string, limit int) string {
+	var field strings.Builder
+	for i := 0; i < len(value) && field.Len() < limit; i++ {
+		if value[i] > ' ' && value[i] < 127 {
+			field.WriteByte(value[i])
+		} else if field.Len() > 0 {
+			field.WriteByte('_')
+		}
+	}
+	if field.Len() == 0 {
+		return "-"
+	}
+	return field.String()
+}
+
+func escapeSyslogControl(s string) string {
+	var escaped strings.Builder
+	for _, r := range s {
+		if r < ' ' || r == 127 {
+			fmt.Fprintf(&escaped, "#%03o", r)
+		} else {
+			escaped.WriteRune(r)
+		}
+	}
+	return escaped.String()
+}
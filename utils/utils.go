@@ -1,7 +1,6 @@
 package utils
 
 import (
-	"archive/zip"
 	"bytes"
 	"encoding/json"
 	"errors"
@@ -120,6 +119,20 @@ func TruncateString(str string, num int) string {
 	return str
 }
 
+// FormatBytes renders a byte count in binary units, e.g. 1.5 MiB.
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
 func RemovePrefixBeforeAPI(url string) string {
 	apiIndex := strings.Index(url, "/api/")
 	if apiIndex == -1 {
@@ -148,6 +161,27 @@ func SaveFile(fileName string, data []byte) error {
 	return nil
 }
 
+// SaveFileFromReader streams r into fileName, creating parent directories as needed.
+func SaveFileFromReader(fileName string, r io.Reader) error {
+	dir := filepath.Dir(fileName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directories: %v", err)
+	}
+
+	file, err := os.Create(fileName)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %v", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	_, err = io.Copy(file, r)
+	if err != nil {
+		return fmt.Errorf("failed to write data to file: %v", err)
+	}
+
+	return file.Close()
+}
+
 func DeleteFile(path string) error {
 	info, err := os.Stat(path)
 	if os.IsNotExist(err) {
@@ -164,121 +198,31 @@ func DeleteFile(path string) error {
 	return os.Remove(path)
 }
 
-func Zip(folderPath string) ([]byte, error) {
-	var buf bytes.Buffer
-	zipWriter := zip.NewWriter(&buf)
-	folderName := filepath.Base(folderPath)
-
-	err := filepath.Walk(folderPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		if path == folderPath {
-			return nil
-		}
-
-		relPath, err := filepath.Rel(folderPath, path)
-		if err != nil {
-			return err
-		}
-
-		zipPath := filepath.Join(folderName, relPath)
-		zipPath = filepath.ToSlash(zipPath)
-
-		if info.IsDir() {
-			zipPath += "/"
-		}
-
-		header, err := zip.FileInfoHeader(info)
-		if err != nil {
-			return err
-		}
-		header.Name = zipPath
-
-		if !info.IsDir() {
-			header.Method = zip.Deflate
-		}
-
-		writer, err := zipWriter.CreateHeader(header)
-		if err != nil {
-			return err
-		}
-
-		if !info.IsDir() {
-			file, err := os.Open(path)
-			if err != nil {
-				return err
-			}
-			defer file.Close()
-
-			_, err = io.Copy(writer, file)
-			if err != nil {
-				return err
-			}
-		}
-
-		return nil
-	})
-
-	if err != nil {
-		zipWriter.Close()
-		return nil, err
+// ParseTimeArg parses a time given on the command line relative to now.
+// It accepts RFC3339 timestamps, dates (2006-01-02) and ages such as 90s, 30m, 2h, 7d or 2w.
+func ParseTimeArg(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
 	}
-
-	err = zipWriter.Close()
-	if err != nil {
-		return nil, err
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
 	}
 
-	return buf.Bytes(), nil
-}
-
-func Unzip(src string, dest string) error {
-	r, err := zip.OpenReader(src)
-	if err != nil {
-		return err
+	units := map[string]time.Duration{
+		"s": time.Second,
+		"m": time.Minute,
+		"h": time.Hour,
+		"d": 24 * time.Hour,
+		"w": 7 * 24 * time.Hour,
 	}
-	defer r.Close()
-
-	for _, f := range r.File {
-		fpath := filepath.Join(dest, f.Name)
-		if f.FileInfo().IsDir() {
-			err := os.MkdirAll(fpath, os.ModePerm)
-			if err != nil {
-				return err
+	if len(value) > 1 {
+		if unit, ok := units[value[len(value)-1:]]; ok {
+			if n, err := strconv.Atoi(value[:len(value)-1]); err == nil && n >= 0 {
+				return now.Add(-time.Duration(n) * unit), nil
 			}
-			continue
-		}
-
-		if err := os.MkdirAll(filepath.Dir(fpath), os.ModePerm); err != nil {
-			return err
 		}
-
-		outFile, err := os.OpenFile(fpath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
-		if err != nil {
-			return err
-		}
-
-		rc, err := f.Open()
-		if err != nil {
-			return err
-		}
-
-		_, err = io.Copy(outFile, rc)
-
-		err = outFile.Close()
-		if err != nil {
-			return err
-		}
-
-		err = rc.Close()
-		if err != nil {
-			return err
-		}
-
 	}
-	return nil
+	return time.Time{}, fmt.Errorf("invalid time %q: use an age like 2h or 7d, a date like 2006-01-02, or an RFC3339 timestamp", value)
 }
 
 func BoolPointerToString(value *bool) string {
@@ -364,16 +308,37 @@ func CreateAndEditTempFile(data []byte) (string, error) {
 		return "", err
 	}
 
-	cmd := exec.Command("vi", tmpl.Name())
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	if err = cmd.Run(); err != nil {
+	if err = EditFile(tmpl.Name()); err != nil {
 		return "", err
 	}
 
 	return tmpl.Name(), nil
 }
 
+// EditFile opens filePath in the editor set by $VISUAL or $EDITOR, falling back to vi, and waits for it to exit.
+// The editor variable may include arguments, such as "code --wait".
+func EditFile(filePath string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	args := strings.Fields(editor)
+	if len(args) == 0 {
+		args = []string{"vi"}
+	}
+
+	cmd := exec.Command(args[0], append(args[1:], filePath)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// ShellQuote quotes s for safe use as a single word in a POSIX shell command.
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
 func SplitPath(path string) (string, string) {
 	parts := strings.SplitN(path, ":", 2)
 	return parts[0], parts[1]
@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestParseTimeArg(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	for value, expected := range map[string]time.Time{
+		"90s":                  now.Add(-90 * time.Second),
+		"2h":                   now.Add(-2 * time.Hour),
+		"7d":                   now.AddDate(0, 0, -7),
+		"1w":                   now.AddDate(0, 0, -7),
+		"2024-03-01T08:00:00Z": time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC),
+		"2024-03-01":           time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local),
+	} {
+		parsed, err := ParseTimeArg(value, now)
+		assert.NoError(t, err, value)
+		assert.True(t, expected.Equal(parsed), value)
+	}
+
+	for _, value := range []string{"", "h", "-2h", "yesterday", "2y"} {
+		_, err := ParseTimeArg(value, now)
+		assert.Error(t, err, value)
+	}
+}